@@ -14,6 +14,7 @@ import (
 	"github.com/joshua-takyi/ww/internal/connect"
 	"github.com/joshua-takyi/ww/internal/container"
 	"github.com/joshua-takyi/ww/internal/routes"
+	"github.com/joshua-takyi/ww/internal/storage"
 )
 
 func main() {
@@ -26,17 +27,18 @@ func main() {
 		slog.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
-	cld, err := connect.CloudinaryCredentials()
-	if err != nil {
-		slog.Error("Failed to connect to Cloudinary", "error", err)
-		os.Exit(1)
-	}
-	connect.Cld = cld
 
 	// Setup logger
 	logger := setupLogger(cfg)
 	logger.Info("Starting Bashbay API server", "environment", cfg.Environment)
 
+	mediaStore, err := setupMediaStore(cfg)
+	if err != nil {
+		logger.Error("Failed to set up media storage", "store", cfg.MediaStore, "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Media storage ready", "store", cfg.MediaStore)
+
 	// Initialize database connections
 	supaClient, supaUrl, supaKey, err := connect.InitSupabase()
 	if err != nil {
@@ -53,7 +55,38 @@ func main() {
 	logger.Info("Connected to MongoDB successfully")
 
 	// Initialize dependency container
-	appContainer := container.NewContainer(logger, cld, supaClient, mongoClient, supaUrl, supaKey)
+	appContainer := container.NewContainer(cfg, logger, mediaStore, supaClient, mongoClient, supaUrl, supaKey)
+
+	// Ensure MongoDB indexes (unique booking per review, etc.)
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := appContainer.ReviewService.EnsureIndexes(indexCtx); err != nil {
+		logger.Warn("Failed to ensure review indexes", "error", err)
+	}
+	if err := appContainer.VenueService.EnsureIndexes(indexCtx); err != nil {
+		logger.Warn("Failed to ensure venue indexes", "error", err)
+	}
+	if err := appContainer.SavedSearchService.EnsureIndexes(indexCtx); err != nil {
+		logger.Warn("Failed to ensure saved search indexes", "error", err)
+	}
+	if err := appContainer.NotificationService.EnsureIndexes(indexCtx); err != nil {
+		logger.Warn("Failed to ensure notification indexes", "error", err)
+	}
+	indexCancel()
+
+	// Pick up word list edits without restarting
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go appContainer.Moderator.Watch(watchCtx, time.Duration(cfg.ModerationReloadSec)*time.Second)
+	// Reveal blind reviews once their window closes and count them in venue ratings
+	go appContainer.ReviewService.WatchBlindReviews(watchCtx, time.Duration(cfg.ReviewRevealMinutes)*time.Minute)
+	// Remove direct uploads that were never confirmed
+	go appContainer.VenueService.WatchExpiredUploads(watchCtx, time.Duration(cfg.UploadGCMinutes)*time.Minute)
+	// Purge venues whose restore window has passed
+	go appContainer.VenueService.WatchDeletedVenues(watchCtx, time.Duration(cfg.VenuePurgeMinutes)*time.Minute)
+	// Build the text search and suggestion indexes and keep them in step with venue edits
+	go appContainer.VenueService.WatchSearchIndex(watchCtx, time.Duration(cfg.SearchIndexMinutes)*time.Minute)
+	// Tell users about newly activated venues matching their saved searches
+	go appContainer.SavedSearchService.WatchSavedSearchAlerts(watchCtx, time.Duration(cfg.SavedSearchAlertMinutes)*time.Minute)
 
 	// Setup routes
 	router := routes.SetupRoutes(appContainer)
@@ -118,3 +151,16 @@ func setupLogger(cfg *config.Config) *slog.Logger {
 
 	return slog.New(handler)
 }
+
+// setupMediaStore picks the media backend from MEDIA_STORE
+func setupMediaStore(cfg *config.Config) (storage.MediaStore, error) {
+	if cfg.MediaStore == storage.BackendLocal {
+		return storage.NewLocalStore(cfg.MediaLocalDir, cfg.MediaBaseURL, cfg.MediaSigningKey)
+	}
+
+	cld, err := connect.CloudinaryCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewCloudinaryStore(cld), nil
+}
@@ -0,0 +1,55 @@
+// Command rebuild-ratings recomputes every venue's rating aggregate from the
+// stored reviews. Run it after bulk review changes or to repair drift in the
+// incrementally maintained aggregates.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/joshua-takyi/ww/internal/config"
+	"github.com/joshua-takyi/ww/internal/connect"
+	"github.com/joshua-takyi/ww/internal/container"
+)
+
+func main() {
+	_ = godotenv.Load(".env.local")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	supaClient, supaUrl, supaKey, err := connect.InitSupabase()
+	if err != nil {
+		logger.Error("Failed to connect to Supabase", "error", err)
+		os.Exit(1)
+	}
+	defer connect.Disconnect()
+
+	mongoClient, err := connect.MongoDBConnect()
+	if err != nil {
+		logger.Error("Failed to connect to MongoDB", "error", err)
+		os.Exit(1)
+	}
+	defer connect.MongoDBDisconnect()
+
+	appContainer := container.NewContainer(cfg, logger, nil, supaClient, mongoClient, supaUrl, supaKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	venues, err := appContainer.ReviewService.RebuildRatingAggregates(ctx)
+	if err != nil {
+		logger.Error("Failed to rebuild rating aggregates", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Rebuilt rating aggregates", "venues", venues, "took", time.Since(start))
+}
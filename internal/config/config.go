@@ -3,19 +3,43 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 type Config struct {
-	Port                string
-	SupabaseURL         string
-	SupabaseAnonKey     string
-	MongoDBURI          string
-	CloudinaryCloudName string
-	CloudinaryAPIKey    string
-	CloudinaryAPISecret string
-	MongoDBPassword     string
-	Environment         string
-	LogLevel            string
+	Port                    string
+	SupabaseURL             string
+	SupabaseAnonKey         string
+	SupabaseServiceRoleKey  string
+	MongoDBURI              string
+	CloudinaryCloudName     string
+	CloudinaryAPIKey        string
+	CloudinaryAPISecret     string
+	MongoDBPassword         string
+	Environment             string
+	LogLevel                string
+	ReviewWindowDays        int
+	ReviewReportLimit       int
+	ResponseEditHours       int
+	ReviewRevealMinutes     int
+	ReviewMaxPhotos         int
+	ReviewMaxPhotoMB        int
+	ProfanityFile           string
+	ProfanityWords          string
+	ModerationReloadSec     int
+	VenueMaxImages          int
+	VenueMaxImageMB         int
+	UploadTicketMinutes     int
+	UploadGCMinutes         int
+	VenueRestoreDays        int
+	VenuePurgeMinutes       int
+	GeoSearchBackend        string
+	SearchIndexMinutes      int
+	SavedSearchAlertMinutes int
+	MediaStore              string
+	MediaLocalDir           string
+	MediaBaseURL            string
+	MediaSigningKey         string
 }
 
 func LoadConfig() (*Config, error) {
@@ -31,6 +55,59 @@ func LoadConfig() (*Config, error) {
 		Environment:         getEnvWithDefault("ENVIRONMENT", "development"),
 
 		LogLevel: getEnvWithDefault("LOG_LEVEL", "info"),
+
+		// Admin actions and background jobs write with the service role, past row level security
+		SupabaseServiceRoleKey: os.Getenv("SUPABASE_SERVICE_ROLE_KEY"),
+
+		// How long after a booking ends the guest may still review it
+		ReviewWindowDays: getEnvIntWithDefault("REVIEW_WINDOW_DAYS", 14),
+		// Number of user reports after which a review is hidden pending moderation
+		ReviewReportLimit: getEnvIntWithDefault("REVIEW_REPORT_LIMIT", 3),
+		// How long a host may edit their response to a review
+		ResponseEditHours: getEnvIntWithDefault("REVIEW_RESPONSE_EDIT_HOURS", 48),
+		// How often blind reviews whose window has closed are revealed and counted in ratings
+		ReviewRevealMinutes: getEnvIntWithDefault("REVIEW_REVEAL_MINUTES", 10),
+		// Photo limits for reviews
+		ReviewMaxPhotos:  getEnvIntWithDefault("REVIEW_MAX_PHOTOS", 6),
+		ReviewMaxPhotoMB: getEnvIntWithDefault("REVIEW_MAX_PHOTO_MB", 5),
+
+		// Base profanity list; locale and allow lists are read from files next to it
+		ProfanityFile:  getEnvWithDefault("PROFANITY_FILE", "config/profanity.txt"),
+		ProfanityWords: os.Getenv("PROFANITY_WORDS"),
+		// How often the word lists are checked for changes; 0 disables hot reloading
+		ModerationReloadSec: getEnvIntWithDefault("MODERATION_RELOAD_SECONDS", 30),
+		// Image limits for venue galleries
+		VenueMaxImages:  getEnvIntWithDefault("VENUE_MAX_IMAGES", 20),
+		VenueMaxImageMB: getEnvIntWithDefault("VENUE_MAX_IMAGE_MB", 10),
+		// Lifetime of direct upload tickets and how often unconfirmed uploads are cleaned up
+		UploadTicketMinutes: getEnvIntWithDefault("UPLOAD_TICKET_MINUTES", 15),
+		UploadGCMinutes:     getEnvIntWithDefault("UPLOAD_GC_MINUTES", 10),
+		// How long deleted venues can be restored, and how often expired ones are purged
+		VenueRestoreDays:  getEnvIntWithDefault("VENUE_RESTORE_DAYS", 30),
+		VenuePurgeMinutes: getEnvIntWithDefault("VENUE_PURGE_MINUTES", 60),
+		// Geographic search: "postgis" runs the venues_in_area function from supabase/migrations,
+		// "haversine" works on any database but reads every venue location, so only outside production
+		GeoSearchBackend: os.Getenv("GEO_SEARCH_BACKEND"),
+		// How often the text search and suggestion indexes are rebuilt from the venues table
+		SearchIndexMinutes: getEnvIntWithDefault("SEARCH_INDEX_MINUTES", 5),
+		// How often newly activated venues are matched against saved searches
+		SavedSearchAlertMinutes: getEnvIntWithDefault("SAVED_SEARCH_ALERT_MINUTES", 15),
+
+		// Where uploaded media is kept: "cloudinary", or "local" to work offline
+		MediaStore: getEnvWithDefault("MEDIA_STORE", "cloudinary"),
+		// Directory and public URL of the local store; files are served by the API
+		MediaLocalDir:   getEnvWithDefault("MEDIA_LOCAL_DIR", "uploads"),
+		MediaBaseURL:    os.Getenv("MEDIA_BASE_URL"),
+		MediaSigningKey: os.Getenv("MEDIA_SIGNING_KEY"),
+	}
+	if cfg.MediaBaseURL == "" {
+		cfg.MediaBaseURL = "http://localhost:" + cfg.Port + "/media"
+	}
+	if cfg.GeoSearchBackend == "" {
+		cfg.GeoSearchBackend = "haversine"
+		if cfg.IsProduction() {
+			cfg.GeoSearchBackend = "postgis"
+		}
 	}
 
 	// Validate required fields
@@ -40,20 +117,38 @@ func LoadConfig() (*Config, error) {
 	if cfg.SupabaseAnonKey == "" {
 		return nil, fmt.Errorf("SUPABASE_URL_ANON_KEY is required")
 	}
+	if cfg.IsProduction() && cfg.SupabaseServiceRoleKey == "" {
+		return nil, fmt.Errorf("SUPABASE_SERVICE_ROLE_KEY is required in production")
+	}
 	if cfg.MongoDBURI == "" {
 		return nil, fmt.Errorf("MONGODB_URI is required")
 	}
 	if cfg.MongoDBPassword == "" {
 		return nil, fmt.Errorf("MONGODB_PASSWORD is required")
 	}
-	if cfg.CloudinaryCloudName == "" {
-		return nil, fmt.Errorf("CLODINARY_CLOUD_NAME is required")
+	switch cfg.MediaStore {
+	case "cloudinary":
+		if cfg.CloudinaryCloudName == "" {
+			return nil, fmt.Errorf("CLODINARY_CLOUD_NAME is required")
+		}
+		if cfg.CloudinaryAPIKey == "" {
+			return nil, fmt.Errorf("CLODINARY_API_KEY is required")
+		}
+		if cfg.CloudinaryAPISecret == "" {
+			return nil, fmt.Errorf("CLODINARY_API_SECRET is required")
+		}
+	case "local":
+		if cfg.IsProduction() && cfg.MediaSigningKey == "" {
+			return nil, fmt.Errorf("MEDIA_SIGNING_KEY is required for local media in production")
+		}
+	default:
+		return nil, fmt.Errorf("MEDIA_STORE must be \"cloudinary\" or \"local\", got %q", cfg.MediaStore)
 	}
-	if cfg.CloudinaryAPIKey == "" {
-		return nil, fmt.Errorf("CLODINARY_API_KEY is required")
+	if cfg.GeoSearchBackend != "haversine" && cfg.GeoSearchBackend != "postgis" {
+		return nil, fmt.Errorf("GEO_SEARCH_BACKEND must be \"haversine\" or \"postgis\", got %q", cfg.GeoSearchBackend)
 	}
-	if cfg.CloudinaryAPISecret == "" {
-		return nil, fmt.Errorf("CLODINARY_API_SECRET is required")
+	if cfg.IsProduction() && cfg.GeoSearchBackend != "postgis" {
+		return nil, fmt.Errorf("GEO_SEARCH_BACKEND must be \"postgis\" in production")
 	}
 
 	// if
@@ -67,6 +162,15 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvIntWithDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
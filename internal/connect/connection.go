@@ -16,7 +16,6 @@ import (
 var (
 	SupabaseClient *supabase.Client
 	MongoDBClient  *mongo.Client
-	Cld            *cloudinary.Cloudinary
 )
 
 // supabase init
@@ -2,30 +2,39 @@ package container
 
 import (
 	"log/slog"
+	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/joshua-takyi/ww/internal/config"
 	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
 	"github.com/joshua-takyi/ww/internal/services"
+	"github.com/joshua-takyi/ww/internal/storage"
 	"github.com/supabase-community/supabase-go"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Container holds all application dependencies
 type Container struct {
-	Logger     *slog.Logger
-	Cloudinary *cloudinary.Cloudinary
+	Logger *slog.Logger
+	Media  storage.MediaStore
 	// Database clients
 	SupabaseClient    *supabase.Client
 	MongoDBClient     *mongo.Client
 	UserService       *services.UserService
 	VenueService      *services.VenuesService
 	FavouritesService *services.FavouriteService
+	ReviewService     *services.ReviewService
+	// Saved searches alert their owners through notifications
+	SavedSearchService  *services.SavedSearchService
+	NotificationService *services.NotificationService
+	Moderator           *moderation.Moderator
 }
 
 // NewContainer creates a new dependency injection container
 func NewContainer(
+	cfg *config.Config,
 	logger *slog.Logger,
-	cloudinary *cloudinary.Cloudinary,
+	media storage.MediaStore,
 	supabaseClient *supabase.Client,
 	mongoDBClient *mongo.Client,
 	supaUrl, supaKey string,
@@ -33,17 +42,60 @@ func NewContainer(
 	// Initialize repositories
 	supa := models.SupabaseNewRepo(supabaseClient, supaUrl, supaKey)
 	mongo := models.MongodbNewRepo(mongoDBClient)
-	userService := services.NewUserService(supa)
-	venueService := services.NewVenuesService(supa, mongo)
+
+	// Without the service role, admin venue transitions and the venue purge job fail
+	if err := supa.SetServiceRoleKey(cfg.SupabaseServiceRoleKey); err != nil {
+		logger.Warn("Failed to set up the Supabase service role client", "error", err)
+	} else if cfg.SupabaseServiceRoleKey == "" {
+		logger.Warn("SUPABASE_SERVICE_ROLE_KEY is not set; admin venue actions are disabled")
+	}
+
+	// A missing or unreadable word list only disables profanity masking
+	wordList, err := moderation.NewWordList(moderation.WordListConfig{
+		File:  cfg.ProfanityFile,
+		Words: cfg.ProfanityWords,
+	})
+	if err != nil {
+		logger.Warn("Failed to load profanity word lists", "error", err)
+	}
+	moderator := moderation.New(nil, wordList, moderation.LinkCheck{}, moderation.ContactCheck{})
+
+	// Geographic search runs in PostGIS; the in-memory locator is for development databases
+	// without the venues_in_area function
+	var locator models.VenueLocator = supa
+	if cfg.GeoSearchBackend == "haversine" {
+		locator = services.NewHaversineLocator(supa)
+	}
+
+	userService := services.NewUserService(supa, moderator, media)
+	venueService := services.NewVenuesService(supa, mongo, mongo, mongo, mongo, mongo, supa, mongo, mongo, locator, moderator, media, services.VenueSettings{
+		MaxImages:       cfg.VenueMaxImages,
+		MaxImageBytes:   int64(cfg.VenueMaxImageMB) << 20,
+		UploadTicketTTL: time.Duration(cfg.UploadTicketMinutes) * time.Minute,
+		RestoreWindow:   time.Duration(cfg.VenueRestoreDays) * 24 * time.Hour,
+	})
 	favouriteService := services.NewFavouriteService(mongo)
+	reviewService := services.NewReviewService(mongo, mongo, supa, mongo, supa, moderator, media, services.ReviewSettings{
+		Window:             time.Duration(cfg.ReviewWindowDays) * 24 * time.Hour,
+		ReportThreshold:    cfg.ReviewReportLimit,
+		ResponseEditWindow: time.Duration(cfg.ResponseEditHours) * time.Hour,
+		MaxPhotos:          cfg.ReviewMaxPhotos,
+		MaxPhotoBytes:      int64(cfg.ReviewMaxPhotoMB) << 20,
+	})
+	notificationService := services.NewNotificationService(mongo)
+	savedSearchService := services.NewSavedSearchService(mongo, mongo, venueService, notificationService)
 
 	return &Container{
-		Logger:            logger,
-		Cloudinary:        cloudinary,
-		SupabaseClient:    supabaseClient,
-		MongoDBClient:     mongoDBClient,
-		UserService:       userService,
-		FavouritesService: favouriteService,
-		VenueService:      venueService,
+		Logger:              logger,
+		Media:               media,
+		SupabaseClient:      supabaseClient,
+		MongoDBClient:       mongoDBClient,
+		UserService:         userService,
+		FavouritesService:   favouriteService,
+		VenueService:        venueService,
+		ReviewService:       reviewService,
+		SavedSearchService:  savedSearchService,
+		NotificationService: notificationService,
+		Moderator:           moderator,
 	}
 }
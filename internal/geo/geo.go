@@ -0,0 +1,95 @@
+// Package geo holds the small amount of spherical geometry used by venue search: great
+// circle distances and map areas given as a radius around a point or a bounding box.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used for distances
+const EarthRadiusKm = 6371.0088
+
+var ErrInvalidArea = errors.New("invalid search area")
+
+// Point is a WGS84 position in degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180 &&
+		!math.IsNaN(p.Lat) && !math.IsNaN(p.Lng)
+}
+
+// Bounds is a map viewport. MinLng may be greater than MaxLng when the box crosses the
+// antimeridian.
+type Bounds struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+func (b Bounds) Valid() bool {
+	return Point{b.MinLat, b.MinLng}.Valid() && Point{b.MaxLat, b.MaxLng}.Valid() && b.MinLat <= b.MaxLat
+}
+
+func (b Bounds) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLng <= b.MaxLng {
+		return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+}
+
+// DistanceKm is the great circle distance between two points (haversine formula)
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Area is where a search looks: within RadiusKm of Center, inside Bounds, or both.
+// Distances are measured from Center when it is set.
+type Area struct {
+	Center   *Point
+	RadiusKm float64
+	Bounds   *Bounds
+}
+
+func (a Area) Validate() error {
+	if a.Center == nil && a.Bounds == nil {
+		return fmt.Errorf("%w: a point or a bounding box is required", ErrInvalidArea)
+	}
+	if a.Center != nil && !a.Center.Valid() {
+		return fmt.Errorf("%w: coordinates are out of range", ErrInvalidArea)
+	}
+	if a.Bounds != nil && !a.Bounds.Valid() {
+		return fmt.Errorf("%w: bounding box is out of range", ErrInvalidArea)
+	}
+	if a.RadiusKm < 0 || math.IsNaN(a.RadiusKm) || (a.RadiusKm > 0 && a.Center == nil) {
+		return fmt.Errorf("%w: radius needs a point and must be positive", ErrInvalidArea)
+	}
+	return nil
+}
+
+// Contains reports whether p lies in the area
+func (a Area) Contains(p Point) bool {
+	if a.Bounds != nil && !a.Bounds.Contains(p) {
+		return false
+	}
+	if a.Center != nil && a.RadiusKm > 0 && DistanceKm(*a.Center, p) > a.RadiusKm {
+		return false
+	}
+	return true
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", Point{5.6037, -0.1870}, Point{5.6037, -0.1870}, 0},
+		{"accra to kumasi", Point{5.6037, -0.1870}, Point{6.6885, -1.6244}, 199.6},
+		{"quarter meridian", Point{0, 0}, Point{90, 0}, math.Pi / 2 * EarthRadiusKm},
+		{"across the antimeridian", Point{0, 179.5}, Point{0, -179.5}, 111.2},
+		{"antipodes", Point{0, 0}, Point{0, 180}, math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKm(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("DistanceKm(%v, %v) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+			}
+			if back := DistanceKm(tt.b, tt.a); math.Abs(back-got) > 1e-9 {
+				t.Errorf("DistanceKm is not symmetric: %.6f and %.6f", got, back)
+			}
+		})
+	}
+}
+
+func TestBoundsContains(t *testing.T) {
+	ghana := Bounds{MinLat: 4.5, MinLng: -3.3, MaxLat: 11.2, MaxLng: 1.2}
+	// Fiji straddles the antimeridian, so the box wraps from east to west
+	fiji := Bounds{MinLat: -21, MinLng: 176, MaxLat: -12, MaxLng: -178}
+
+	tests := []struct {
+		name   string
+		bounds Bounds
+		point  Point
+		want   bool
+	}{
+		{"inside", ghana, Point{5.6, -0.19}, true},
+		{"on the edge", ghana, Point{4.5, 1.2}, true},
+		{"north of the box", ghana, Point{12, 0}, false},
+		{"east of the box", ghana, Point{6, 2}, false},
+		{"wrapped box, east of the antimeridian", fiji, Point{-18, 178.4}, true},
+		{"wrapped box, west of the antimeridian", fiji, Point{-16, -179.9}, true},
+		{"wrapped box, on the antimeridian", fiji, Point{-17, 180}, true},
+		{"wrapped box, outside in longitude", fiji, Point{-18, 0}, false},
+		{"wrapped box, outside in latitude", fiji, Point{-25, 179}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bounds.Contains(tt.point); got != tt.want {
+				t.Errorf("%+v.Contains(%v) = %v, want %v", tt.bounds, tt.point, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// currentUser extracts the authenticated user's claims and parsed ID from the context.
+// It writes the error response itself and returns ok=false when the request should stop.
+func currentUser(c *gin.Context) (*helpers.EnhancedClaims, uuid.UUID, bool) {
+	userClaims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse("unauthorized"))
+		return nil, uuid.Nil, false
+	}
+
+	claims, ok := userClaims.(*helpers.EnhancedClaims)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse("invalid user claims"))
+		return nil, uuid.Nil, false
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid user ID in token"))
+		return nil, uuid.Nil, false
+	}
+
+	return claims, userId, true
+}
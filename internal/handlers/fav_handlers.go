@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/models"
 	"github.com/joshua-takyi/ww/internal/services"
 )
 
@@ -101,11 +104,20 @@ func GetUserFavourites(f *services.FavouriteService) gin.HandlerFunc {
 			return
 		}
 
-		res, err := f.GetFavouritesByUserID(c.Request.Context(), parsedUserId)
+		page, ok := parsePageRequest(c, 20, false)
+		if !ok {
+			return
+		}
+
+		items, info, err := f.ListFavouriteItems(c.Request.Context(), parsedUserId, page)
 		if err != nil {
+			if errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(200, res)
+		c.JSON(200, pageResponse(items, page, info))
 	}
 }
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/services"
+)
+
+// CreateGuestReview lets a venue host rate the guest of a completed booking
+func CreateGuestReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		var review models.GuestReview
+		if err := c.ShouldBindJSON(&review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+		if err := models.Validate.Struct(review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		created, err := r.CreateGuestReview(c.Request.Context(), userId, &review, accessToken)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.SuccessResponse(created, "Guest review submitted successfully"))
+	}
+}
+
+// GetMyGuestReviews lists the revealed reviews hosts have written about the caller
+func GetMyGuestReviews(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		reviews, err := r.GetGuestReviewsAbout(c.Request.Context(), userId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(reviews, ""))
+	}
+}
+
+// GetWrittenGuestReviews lists the guest reviews the calling host has written
+func GetWrittenGuestReviews(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		reviews, err := r.GetGuestReviewsWritten(c.Request.Context(), userId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(reviews, ""))
+	}
+}
+
+// GetGuestReputation returns a guest's reputation summary (hosts and admins only)
+func GetGuestReputation(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsHost() && !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("access denied"))
+			return
+		}
+
+		guestId, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid guest ID format"))
+			return
+		}
+
+		reputation, err := r.GetGuestReputation(c.Request.Context(), guestId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(reputation, ""))
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetNotifications returns the current user's notifications, newest first
+func GetNotifications(n *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		page, ok := parsePageRequest(c, 20, false)
+		if !ok {
+			return
+		}
+
+		notifications, info, err := n.ListNotifications(c.Request.Context(), userId, page)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, pageResponse(notifications, page, info))
+	}
+}
+
+// GetUnreadNotificationCount returns how many of the current user's notifications are unread
+func GetUnreadNotificationCount(n *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		count, err := n.CountUnread(c.Request.Context(), userId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"unread": count}, ""))
+	}
+}
+
+func MarkNotificationRead(n *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		id, err := primitive.ObjectIDFromHex(strings.TrimSpace(c.Param("id")))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid notification ID format"))
+			return
+		}
+
+		if err := n.MarkRead(c.Request.Context(), userId, id); err != nil {
+			if errors.Is(err, models.ErrNotificationNotFound) {
+				c.JSON(http.StatusNotFound, models.ErrorResponse(err.Error()))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(nil, "notification marked read"))
+	}
+}
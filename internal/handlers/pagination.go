@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// parsePageRequest reads cursor, limit, offset and include_total. Offset is only used by
+// requests without a cursor, and only accepted by lists that can page by offset (withOffset);
+// lists paged by cursor alone reject it. It writes the error response itself and returns
+// ok=false when the request should stop.
+func parsePageRequest(c *gin.Context, defaultLimit int, withOffset bool) (models.PageRequest, bool) {
+	page := models.PageRequest{
+		Cursor:    c.Query("cursor"),
+		Limit:     defaultLimit,
+		WithTotal: c.Query("include_total") == "true",
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		limitInt, err := strconv.Atoi(limit)
+		if err != nil || limitInt <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
+			return page, false
+		}
+		page.Limit = limitInt
+	}
+	if offset := c.Query("offset"); offset != "" && page.Cursor == "" {
+		if !withOffset {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("offset is not supported, page with cursor instead"))
+			return page, false
+		}
+		offsetInt, err := strconv.Atoi(offset)
+		if err != nil || offsetInt < 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid offset parameter"))
+			return page, false
+		}
+		page.Offset = offsetInt
+	}
+
+	return page, true
+}
+
+// pageResponse wraps a page of a cursor paginated list. Requests paging by offset also get
+// the page number.
+func pageResponse(data interface{}, page models.PageRequest, info models.PageInfo) models.ApiResponse {
+	number := 0
+	if page.Cursor == "" {
+		number = page.Offset/page.Limit + 1
+	}
+	resp := models.PaginatedResponse(data, number, page.Limit, info.Total).
+		WithCursors(info.NextCursor, info.PrevCursor)
+	resp.Truncated = info.Truncated
+	return resp
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
+	"github.com/joshua-takyi/ww/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reviewErrorStatus maps review service errors to HTTP status codes
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBookingRequired),
+		errors.Is(err, services.ErrBookingVenueMismatch),
+		errors.Is(err, services.ErrBookingNotCompleted),
+		errors.Is(err, services.ErrReviewWindowClosed),
+		errors.Is(err, services.ErrRejectionReason),
+		errors.Is(err, services.ErrInvalidReviewStatus),
+		errors.Is(err, services.ErrCannotReportOwn),
+		errors.Is(err, services.ErrReviewNotPublic),
+		errors.Is(err, services.ErrResponseRequired),
+		errors.Is(err, services.ErrNoPhotos),
+		errors.Is(err, services.ErrTooManyPhotos),
+		errors.Is(err, services.ErrUnsupportedPhoto):
+		return http.StatusBadRequest
+	case errors.Is(err, moderation.ErrContentHeld):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrPhotoTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrPhotoNotFound),
+		errors.Is(err, models.ErrReviewNotFound),
+		errors.Is(err, models.ErrBookingNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrBookingNotOwned),
+		errors.Is(err, services.ErrNotVenueHost),
+		errors.Is(err, services.ErrNotBookingHost),
+		errors.Is(err, services.ErrResponseEditClosed):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrReviewAlreadyExists),
+		errors.Is(err, models.ErrAlreadyReported),
+		errors.Is(err, services.ErrGuestReviewAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parseReviewID(c *gin.Context) (primitive.ObjectID, bool) {
+	reviewID := strings.TrimSpace(c.Param("id"))
+	parsed, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid review ID format"))
+		return primitive.NilObjectID, false
+	}
+	return parsed, true
+}
+
+func CreateReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		var review models.VenueReview
+		if err := c.ShouldBindJSON(&review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+		if err := models.Validate.Struct(review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+		if review.VenueID == uuid.Nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("venue ID is required"))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		created, err := r.CreateReview(c.Request.Context(), userId, review.VenueID, &review, accessToken)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.SuccessResponse(created, "Review submitted for moderation"))
+	}
+}
+
+func GetVenueReviews(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		venueID := strings.TrimSpace(c.Param("id"))
+		parsedId, err := uuid.Parse(venueID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid venue ID format"))
+			return
+		}
+
+		reviews, err := r.GetReviewsByVenue(c.Request.Context(), parsedId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(reviews, ""))
+	}
+}
+
+func GetMyReviews(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		reviews, err := r.GetReviewsByUser(c.Request.Context(), userId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(reviews, ""))
+	}
+}
+
+func UpdateReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		var review models.VenueReview
+		if err := c.ShouldBindJSON(&review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+		if err := models.Validate.Struct(review); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		updated, err := r.UpdateReview(c.Request.Context(), userId, reviewId, &review)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(updated, "Review updated successfully"))
+	}
+}
+
+func DeleteReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		if err := r.DeleteReview(c.Request.Context(), userId, reviewId); err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(nil, "Review deleted successfully"))
+	}
+}
+
+func ReportReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		if _, err := r.ReportReview(c.Request.Context(), userId, reviewId, req.Reason); err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(nil, "Review reported successfully"))
+	}
+}
+
+// GetReviewModerationQueue lists reviews by moderation status (admin only)
+func GetReviewModerationQueue(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("access denied"))
+			return
+		}
+
+		limit := c.DefaultQuery("limit", "20")
+		offset := c.DefaultQuery("offset", "0")
+		limitInt, err := strconv.Atoi(limit)
+		if err != nil || limitInt <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
+			return
+		}
+		offsetInt, err := strconv.Atoi(offset)
+		if err != nil || offsetInt < 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid offset parameter"))
+			return
+		}
+
+		reviews, total, err := r.GetModerationQueue(c.Request.Context(), c.Query("status"), offsetInt, limitInt)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		page := (offsetInt / limitInt) + 1
+		c.JSON(http.StatusOK, models.PaginatedResponse(reviews, page, limitInt, total))
+	}
+}
+
+// ModerateReview approves or rejects a review (admin only)
+func ModerateReview(r *services.ReviewService, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("access denied"))
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		// The body is optional when approving
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
+		}
+
+		review, err := r.ModerateReview(c.Request.Context(), userId, reviewId, approve, req.Reason)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		message := "Review approved"
+		if !approve {
+			message = "Review rejected"
+		}
+		c.JSON(http.StatusOK, models.SuccessResponse(review, message))
+	}
+}
+
+// RespondToReview creates or edits the venue host's reply to a review
+func RespondToReview(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Body string `json:"body" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		review, err := r.RespondToReview(c.Request.Context(), userId, reviewId, req.Body, claims.IsAdmin())
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(review, "Response saved successfully"))
+	}
+}
+
+func DeleteReviewResponse(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		review, err := r.DeleteReviewResponse(c.Request.Context(), userId, reviewId, claims.IsAdmin())
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(review, "Response removed successfully"))
+	}
+}
+
+// UploadReviewPhotos attaches multipart "photos" files to the caller's review
+func UploadReviewPhotos(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("photos must be sent as multipart form data"))
+			return
+		}
+
+		review, err := r.AddReviewPhotos(c.Request.Context(), userId, reviewId, form.File["photos"])
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(review, "Photos uploaded and submitted for moderation"))
+	}
+}
+
+func DeleteReviewPhoto(r *services.ReviewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		reviewId, ok := parseReviewID(c)
+		if !ok {
+			return
+		}
+
+		index, err := strconv.Atoi(c.Param("index"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid photo index"))
+			return
+		}
+
+		review, err := r.RemoveReviewPhoto(c.Request.Context(), userId, reviewId, index)
+		if err != nil {
+			c.JSON(reviewErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(review, "Photo removed successfully"))
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// savedSearchParams are the /venues/search parameters kept with a saved search. Paging and
+// facet parameters belong to a single request, not to the search.
+var savedSearchParams = []string{
+	"q", "venue_type", "amenities", "min_price", "max_price", "min_capacity", "max_capacity",
+	"guests", "date", "start_time", "end_time", "location", "name", "description", "region",
+	"sort", "lat", "lng", "radius_km", "bbox",
+}
+
+func savedSearchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidSavedSearchName),
+		errors.Is(err, models.ErrInvalidVenueSearch),
+		errors.Is(err, geo.ErrInvalidArea):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrTooManySavedSearches):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrSavedSearchNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parseSavedSearchID(c *gin.Context) (primitive.ObjectID, bool) {
+	parsed, err := primitive.ObjectIDFromHex(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid saved search ID format"))
+		return primitive.NilObjectID, false
+	}
+	return parsed, true
+}
+
+// CreateSavedSearch saves a venue search given as the query string of /venues/search
+func CreateSavedSearch(s *services.SavedSearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		var body struct {
+			Name  string `json:"name" binding:"required"`
+			Query string `json:"query" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid request body: "+err.Error()))
+			return
+		}
+
+		values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(body.Query), "?"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid query: "+err.Error()))
+			return
+		}
+		query, err := parseVenueSearchQuery(values)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+		area, err := parseSearchArea(values)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		// Keep only the search itself, in a stable order
+		params := url.Values{}
+		for _, key := range savedSearchParams {
+			if list, ok := values[key]; ok {
+				params[key] = list
+			}
+		}
+
+		saved, err := s.CreateSavedSearch(c.Request.Context(), userId, body.Name, params.Encode(), query, area)
+		if err != nil {
+			c.JSON(savedSearchErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.SuccessResponse(saved, "search saved"))
+	}
+}
+
+func GetSavedSearches(s *services.SavedSearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		searches, err := s.ListSavedSearches(c.Request.Context(), userId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(searches, ""))
+	}
+}
+
+func RenameSavedSearch(s *services.SavedSearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		id, ok := parseSavedSearchID(c)
+		if !ok {
+			return
+		}
+
+		var body struct {
+			Name string `json:"name" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid request body: "+err.Error()))
+			return
+		}
+
+		saved, err := s.RenameSavedSearch(c.Request.Context(), userId, id, body.Name)
+		if err != nil {
+			c.JSON(savedSearchErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(saved, "saved search renamed"))
+	}
+}
+
+func DeleteSavedSearch(s *services.SavedSearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		id, ok := parseSavedSearchID(c)
+		if !ok {
+			return
+		}
+
+		if err := s.DeleteSavedSearch(c.Request.Context(), userId, id); err != nil {
+			c.JSON(savedSearchErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(nil, "saved search deleted"))
+	}
+}
@@ -1,15 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"os"
 	"strings"
 
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/imaging"
 	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
 	"github.com/joshua-takyi/ww/internal/services"
 	"github.com/supabase-community/gotrue-go/types"
 )
@@ -24,6 +26,10 @@ func CreateUser(u *services.UserService) gin.HandlerFunc {
 
 		createdUser, err := u.CreateUser(&user)
 		if err != nil {
+			if errors.Is(err, moderation.ErrContentHeld) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
@@ -201,6 +207,10 @@ func UpdateUser(u *services.UserService) gin.HandlerFunc {
 
 		data, err := u.UpdateUser(c.Request.Context(), user, parsedParamId, accessToken)
 		if err != nil {
+			if errors.Is(err, moderation.ErrContentHeld) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
@@ -254,7 +264,7 @@ func DeleteUser(u *services.UserService) gin.HandlerFunc {
 	}
 }
 
-func UploadAvatar(u *services.UserService, cld *cloudinary.Cloudinary) gin.HandlerFunc {
+func UploadAvatar(u *services.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		paramId := helpers.StringTrim(c.Param("id"))
 		if paramId == "" {
@@ -315,8 +325,12 @@ func UploadAvatar(u *services.UserService, cld *cloudinary.Cloudinary) gin.Handl
 			return
 		}
 
-		avatarURL, err := u.UploadAvatar(c.Request.Context(), userId, tempFile.Name(), accessToken, cld)
+		avatarURL, err := u.UploadAvatar(c.Request.Context(), userId, tempFile.Name(), accessToken)
 		if err != nil {
+			if errors.Is(err, imaging.ErrUnsupportedImage) || errors.Is(err, imaging.ErrImageTooLarge) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
@@ -2,18 +2,77 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
 	"github.com/joshua-takyi/ww/internal/helpers"
 	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
 	"github.com/joshua-takyi/ww/internal/services"
 )
 
+// venueErrorStatus maps venue service errors to HTTP status codes
+func venueErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNoVenueChanges),
+		errors.Is(err, services.ErrInvalidVenueField),
+		errors.Is(err, services.ErrNoVenueImages),
+		errors.Is(err, services.ErrTooManyVenueImages),
+		errors.Is(err, services.ErrVenueImagesOnCreate),
+		errors.Is(err, services.ErrUnsupportedVenueImage),
+		errors.Is(err, services.ErrInvalidImageOrder),
+		errors.Is(err, services.ErrInvalidUploadCount),
+		errors.Is(err, services.ErrVenueRejectionReason),
+		errors.Is(err, services.ErrInvalidVenueStatus):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidStatusTransition),
+		errors.Is(err, services.ErrVenueHasBookings),
+		errors.Is(err, services.ErrVenueNotDeleted):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrRestoreWindowClosed):
+		return http.StatusGone
+	case errors.Is(err, services.ErrVenueSuspended):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrUploadNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrUploadExpired):
+		return http.StatusGone
+	case errors.Is(err, services.ErrUploadNotReceived):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrVenueImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrVenueImageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNotVenueOwner):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrVenueNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, moderation.ErrContentHeld),
+		errors.Is(err, services.ErrVenueIncomplete):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parseVenueID(c *gin.Context) (uuid.UUID, bool) {
+	venueID := strings.Trim(strings.TrimSpace(c.Param("id")), "\"'")
+	parsed, err := uuid.Parse(venueID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid venue ID format"))
+		return uuid.Nil, false
+	}
+	return parsed, true
+}
+
 func CreateVenueHandler(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var venue models.Venue
@@ -47,7 +106,7 @@ func CreateVenueHandler(v *services.VenuesService) gin.HandlerFunc {
 
 		createdVenue, err := v.CreateVenue(c.Request.Context(), &venue, parsedId, accessToken)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 
@@ -57,32 +116,33 @@ func CreateVenueHandler(v *services.VenuesService) gin.HandlerFunc {
 
 func ListVenues(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Parse pagination parameters
-		limit := c.DefaultQuery("limit", "10")
-		offset := c.DefaultQuery("offset", "0")
-		limitInt, err := strconv.Atoi(limit)
-		if err != nil || limitInt <= 0 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
-			return
-		}
-		offsetInt, err := strconv.Atoi(offset)
-		if err != nil || offsetInt < 0 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid offset parameter"))
+		page, ok := parsePageRequest(c, 10, true)
+		if !ok {
 			return
 		}
-		venues, total, err := v.ListVenues(c.Request.Context(), offsetInt, limitInt)
+
+		sortBy := models.VenueSort(c.Query("sort"))
+		venues, info, err := v.ListVenues(c.Request.Context(), sortBy, page)
 		if err != nil {
+			if errors.Is(err, models.ErrInvalidVenueSearch) || errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
 			return
 		}
 
-		page := (offsetInt / limitInt) + 1
-		c.JSON(http.StatusOK, models.PaginatedResponse(venues, page, limitInt, total))
+		c.JSON(http.StatusOK, pageResponse(venues, page, info))
 	}
 }
 
 func ListVenueByID(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
 		venueID := c.Param("id")
 		// Normalize incoming id: trim spaces and surrounding quotes which may occur
 		// when clients pass values as JSON strings or templates.
@@ -102,9 +162,9 @@ func ListVenueByID(v *services.VenuesService) gin.HandlerFunc {
 			return
 		}
 
-		venue, err := v.ListVenueByID(c.Request.Context(), parsedId)
+		venue, err := v.ListVenueByID(c.Request.Context(), parsedId, userId, claims.IsAdmin())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 		if venue == nil {
@@ -116,67 +176,76 @@ func ListVenueByID(v *services.VenuesService) gin.HandlerFunc {
 	}
 }
 
+// DeleteVenue moves a venue to the trash, or deactivates it when bookings are upcoming
 func DeleteVenue(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		venueID := c.Param("id")
-		venueID = strings.TrimSpace(venueID)
-		venueID = strings.Trim(venueID, "\"'")
-		if venueID == "" {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("venue ID is required"))
-			return
-		}
-
-		userClaims, exists := c.Get("user")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse("unauthorized"))
+		claims, userId, ok := currentUser(c)
+		if !ok {
 			return
 		}
-
-		claims, ok := userClaims.(*helpers.EnhancedClaims)
+		venueId, ok := parseVenueID(c)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse("invalid user claims"))
 			return
 		}
 
-		userId, err := uuid.Parse(claims.UserID)
+		// Extract access token cookie to allow repo to perform the delete under the user's session
+		accessToken, _ := c.Cookie("access_token")
+
+		deletion, err := v.DeleteVenue(c.Request.Context(), userId, venueId, claims.IsAdmin(), accessToken)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid user ID in token"))
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 
-		parsedId, err := uuid.Parse(venueID)
-		if err != nil {
-			fmt.Printf("failed to parse venue id for delete: %q, error: %v\n", venueID, err)
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid venue ID format"))
-			return
+		message := "venue deleted successfully"
+		if deletion.Deactivated {
+			message = "venue has upcoming bookings, so it was deactivated instead of deleted"
 		}
+		c.JSON(http.StatusOK, models.SuccessResponse(deletion, message))
+	}
+}
 
-		// Get the venue first to verify ownership
-		venue, err := v.ListVenueByID(c.Request.Context(), parsedId)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+// RestoreVenue takes a deleted venue out of the trash
+func RestoreVenue(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
 			return
 		}
-		if venue == nil {
-			c.JSON(http.StatusNotFound, models.ErrorResponse("venue not found"))
+		venueId, ok := parseVenueID(c)
+		if !ok {
 			return
 		}
 
-		// Check if the user owns the venue
-		if venue.HostId != userId && !claims.IsAdmin() {
-			c.JSON(http.StatusForbidden, models.ErrorResponse("forbidden: you can only delete your own venues"))
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.RestoreVenue(c.Request.Context(), userId, venueId, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "venue restored"))
+	}
+}
+
+// ListDeletedVenues lists the current host's venues that can still be restored
+func ListDeletedVenues(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, userId, ok := currentUser(c)
+		if !ok {
 			return
 		}
 
-		// Extract access token cookie to allow repo to perform the delete under the user's session
 		accessToken, _ := c.Cookie("access_token")
 
-		if err := v.DeleteVenue(c.Request.Context(), userId, parsedId, accessToken); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+		venues, err := v.ListDeletedVenues(c.Request.Context(), userId, accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 
-		c.JSON(http.StatusOK, models.SuccessResponse(nil, "venue deleted successfully"))
+		c.JSON(http.StatusOK, models.SuccessResponse(venues, ""))
 	}
 }
 
@@ -246,92 +315,214 @@ func ListVenuesByHost(v *services.VenuesService) gin.HandlerFunc {
 
 func QueryVenues(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Parse pagination parameters
-		limit := c.DefaultQuery("limit", "10")
-		offset := c.DefaultQuery("offset", "0")
-		limitInt, err := strconv.Atoi(limit)
-		if err != nil || limitInt <= 0 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
+		page, ok := parsePageRequest(c, 10, true)
+		if !ok {
 			return
 		}
-		offsetInt, err := strconv.Atoi(offset)
-		if err != nil || offsetInt < 0 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid offset parameter"))
+
+		query, err := parseVenueSearchQuery(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
 			return
 		}
 
-		// Build query map from request parameters
-		query := make(map[string]interface{})
+		// Geographic filter: lat/lng with optional radius_km, and/or a bbox viewport
+		area, err := parseSearchArea(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
 
-		// Venue type filter
-		if venueType := c.Query("venue_type"); venueType != "" {
-			query["venue_type"] = venueType
+		// If no query parameters provided, return bad request
+		if query.IsEmpty() && area == nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("at least one query parameter is required"))
+			return
 		}
 
-		// Price range filters
-		if minPrice := c.Query("min_price"); minPrice != "" {
-			if minPriceFloat, err := strconv.ParseFloat(minPrice, 64); err == nil {
-				query["min_price"] = minPriceFloat
+		venues, info, err := v.QueryVenues(c.Request.Context(), query, area, page)
+		if err != nil {
+			if errors.Is(err, geo.ErrInvalidArea) || errors.Is(err, models.ErrInvalidVenueSearch) ||
+				errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
 			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
 		}
-		if maxPrice := c.Query("max_price"); maxPrice != "" {
-			if maxPriceFloat, err := strconv.ParseFloat(maxPrice, 64); err == nil {
-				query["max_price"] = maxPriceFloat
+
+		response := pageResponse(venues, page, info)
+		// Filter counts are optional, as they cost another pass over the matches
+		if c.Query("facets") == "true" {
+			facets, err := v.QueryVenueFacets(c.Request.Context(), query, area)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+				return
 			}
+			response = response.WithFacets(facets)
 		}
 
-		// Capacity range filters
-		if minCapacity := c.Query("min_capacity"); minCapacity != "" {
-			if minCapInt, err := strconv.Atoi(minCapacity); err == nil {
-				query["min_capacity"] = minCapInt
-			}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ListAmenities returns the amenities catalogue venues record their amenities against
+func ListAmenities() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, models.SuccessResponse(models.AmenityCatalogue, ""))
+	}
+}
+
+// SuggestSearches completes the search box from the in-memory suggestion index
+func SuggestSearches(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "8"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
+			return
 		}
-		if maxCapacity := c.Query("max_capacity"); maxCapacity != "" {
-			if maxCapInt, err := strconv.Atoi(maxCapacity); err == nil {
-				query["max_capacity"] = maxCapInt
+
+		suggestions, err := v.SuggestSearches(c.Request.Context(), c.Query("q"), limit)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidVenueSearch) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
 			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			return
 		}
 
-		// Location filter (partial match)
-		if location := c.Query("location"); location != "" {
-			query["location"] = location
-		}
+		c.JSON(http.StatusOK, models.SuccessResponse(suggestions, ""))
+	}
+}
 
-		// Amenities filter (comma-separated)
-		if amenities := c.Query("amenities"); amenities != "" {
-			query["amenities"] = strings.Split(amenities, ",")
-		}
+// parseVenueSearchQuery reads the search filters. List filters accept comma separated values
+// and repeated parameters alike; malformed numbers are rejected rather than ignored.
+func parseVenueSearchQuery(values url.Values) (models.VenueSearchQuery, error) {
+	query := models.VenueSearchQuery{
+		Text:        strings.TrimSpace(values.Get("q")),
+		VenueTypes:  queryList(values, "venue_type"),
+		Location:    strings.TrimSpace(values.Get("location")),
+		Name:        strings.TrimSpace(values.Get("name")),
+		Description: strings.TrimSpace(values.Get("description")),
+		Region:      strings.TrimSpace(values.Get("region")),
+		Sort:        models.VenueSort(values.Get("sort")),
+	}
 
-		// Status filter
-		if status := c.Query("status"); status != "" {
-			query["status"] = status
-		}
+	// Amenity filters: "wifi", "wifi=true", "parking>=10"
+	for _, expr := range queryList(values, "amenities") {
+		query.Amenities = append(query.Amenities, models.ParseAmenityFilter(expr))
+	}
 
-		// Name filter (partial match)
-		if name := c.Query("name"); name != "" {
-			query["name"] = name
-		}
+	var err error
+	if query.MinPrice, err = queryFloat(values, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = queryFloat(values, "max_price"); err != nil {
+		return query, err
+	}
+	if query.MinCapacity, err = queryInt(values, "min_capacity"); err != nil {
+		return query, err
+	}
+	if query.MaxCapacity, err = queryInt(values, "max_capacity"); err != nil {
+		return query, err
+	}
+	if query.Guests, err = queryInt(values, "guests"); err != nil {
+		return query, err
+	}
 
-		// Description filter (partial match)
-		if description := c.Query("description"); description != "" {
-			query["description"] = description
-		}
+	// Availability: free on date, optionally between start_time and end_time
+	window := models.AvailabilityWindow{
+		Date:  strings.TrimSpace(values.Get("date")),
+		Start: strings.TrimSpace(values.Get("start_time")),
+		End:   strings.TrimSpace(values.Get("end_time")),
+	}
+	if window != (models.AvailabilityWindow{}) {
+		query.Window = &window
+	}
 
-		// If no query parameters provided, return bad request
-		if len(query) == 0 {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse("at least one query parameter is required"))
-			return
+	return query, query.Validate()
+}
+
+func queryList(values url.Values, key string) []string {
+	var list []string
+	for _, raw := range values[key] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				list = append(list, value)
+			}
 		}
+	}
+	return list
+}
 
-		venues, total, err := v.QueryVenues(c.Request.Context(), query, offsetInt, limitInt)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
-			return
+func queryFloat(values url.Values, key string) (*float64, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("%w: %s must be a number", models.ErrInvalidVenueSearch, key)
+	}
+	return &value, nil
+}
+
+func queryInt(values url.Values, key string) (*int, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a whole number", models.ErrInvalidVenueSearch, key)
+	}
+	return &value, nil
+}
+
+// parseSearchArea reads lat, lng and radius_km and a bbox given as
+// "minLng,minLat,maxLng,maxLat". It returns nil when none of them are present.
+func parseSearchArea(values url.Values) (*geo.Area, error) {
+	lat, lng, radius, bbox := values.Get("lat"), values.Get("lng"), values.Get("radius_km"), values.Get("bbox")
+	if lat == "" && lng == "" && radius == "" && bbox == "" {
+		return nil, nil
+	}
+
+	area := &geo.Area{}
+	if lat != "" || lng != "" {
+		latFloat, latErr := strconv.ParseFloat(lat, 64)
+		lngFloat, lngErr := strconv.ParseFloat(lng, 64)
+		if latErr != nil || lngErr != nil {
+			return nil, fmt.Errorf("%w: lat and lng must both be numbers", geo.ErrInvalidArea)
+		}
+		area.Center = &geo.Point{Lat: latFloat, Lng: lngFloat}
+	}
+	if radius != "" {
+		radiusFloat, err := strconv.ParseFloat(radius, 64)
+		if err != nil || radiusFloat <= 0 {
+			return nil, fmt.Errorf("%w: radius_km must be a positive number", geo.ErrInvalidArea)
+		}
+		area.RadiusKm = radiusFloat
+	}
+	if bbox != "" {
+		parts := strings.Split(bbox, ",")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("%w: bbox must be minLng,minLat,maxLng,maxLat", geo.ErrInvalidArea)
+		}
+		values := make([]float64, 4)
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bbox must be minLng,minLat,maxLng,maxLat", geo.ErrInvalidArea)
+			}
+			values[i] = value
 		}
+		area.Bounds = &geo.Bounds{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	}
 
-		page := (offsetInt / limitInt) + 1
-		c.JSON(http.StatusOK, models.PaginatedResponse(venues, page, limitInt, total))
+	if err := area.Validate(); err != nil {
+		return nil, err
 	}
+	return area, nil
 }
 
 func GetVenueBySlug(v *services.VenuesService) gin.HandlerFunc {
@@ -346,18 +537,476 @@ func GetVenueBySlug(v *services.VenuesService) gin.HandlerFunc {
 
 		venue, err := v.GetVenueBySlug(c.Request.Context(), slug)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 		if venue == nil {
 			c.JSON(http.StatusNotFound, models.ErrorResponse("venue not found"))
 			return
 		}
+		// Renamed venues answer their old slug with a permanent redirect
+		if venue.Slug != slug {
+			c.Redirect(http.StatusMovedPermanently, strings.TrimSuffix(c.FullPath(), ":slug")+url.PathEscape(venue.Slug))
+			return
+		}
 
 		c.JSON(http.StatusOK, models.SuccessResponse(venue, ""))
 	}
 }
 
+// UpdateVenue applies a partial update; ownership and field rules are enforced by the service
+func UpdateVenue(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		parsedId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var updates map[string]interface{}
+		if err := c.ShouldBindJSON(&updates); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.UpdateVenue(c.Request.Context(), userId, parsedId, updates, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "venue updated successfully"))
+	}
+}
+
+// UploadVenueImages appends multipart "images" files to a venue's gallery
+func UploadVenueImages(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("images must be sent as multipart form data"))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.AddVenueImages(c.Request.Context(), userId, venueId, form.File["images"], claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "images uploaded successfully"))
+	}
+}
+
+// IssueVenueImageUploads returns signed tickets for uploading gallery images directly to storage
+func IssueVenueImageUploads(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Count int `json:"count" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		tickets, err := v.IssueVenueImageUploads(c.Request.Context(), userId, venueId, req.Count, claims.IsAdmin())
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.SuccessResponse(tickets, "upload tickets issued"))
+	}
+}
+
+// ConfirmVenueImageUploads attaches directly uploaded images to the venue's gallery
+func ConfirmVenueImageUploads(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			PublicIDs []string `json:"public_ids" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.ConfirmVenueImageUploads(c.Request.Context(), userId, venueId, req.PublicIDs, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "images added successfully"))
+	}
+}
+
+// ReorderVenueImages sets the gallery order from the full list of image URLs
+func ReorderVenueImages(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Images []string `json:"images" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.ReorderVenueImages(c.Request.Context(), userId, venueId, req.Images, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "images reordered successfully"))
+	}
+}
+
+// SetVenueCoverImage makes the image at the given index the venue's cover
+func SetVenueCoverImage(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Index *int `json:"index" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.SetVenueCoverImage(c.Request.Context(), userId, venueId, *req.Index, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "cover image updated successfully"))
+	}
+}
+
+func DeleteVenueImage(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		index, err := strconv.Atoi(c.Param("index"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid image index"))
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.RemoveVenueImage(c.Request.Context(), userId, venueId, index, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "image removed successfully"))
+	}
+}
+
+// CreateVenueDraft saves a partially filled in listing for the listing wizard
+func CreateVenueDraft(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsHost() && !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("only users with host role can create venues"))
+			return
+		}
+
+		fields := map[string]interface{}{}
+		// An empty draft is allowed; the wizard fills it in step by step
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&fields); err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.CreateVenueDraft(c.Request.Context(), userId, fields, accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusCreated, models.SuccessResponse(venue, "draft saved"))
+	}
+}
+
+// GetVenueCompleteness scores a listing against the wizard's required and recommended fields
+func GetVenueCompleteness(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		completeness, err := v.GetVenueCompleteness(c.Request.Context(), userId, venueId, claims.IsAdmin())
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(completeness, ""))
+	}
+}
+
+// SubmitVenueDraft sends a completed draft to the admin review queue
+func SubmitVenueDraft(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.SubmitVenueDraft(c.Request.Context(), userId, venueId, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "venue submitted for review"))
+	}
+}
+
+// DeactivateVenue takes an active venue off the public listings
+func DeactivateVenue(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		// The body is optional
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.DeactivateVenue(c.Request.Context(), userId, venueId, req.Reason, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "venue deactivated"))
+	}
+}
+
+// ReactivateVenue puts a deactivated venue back on the public listings
+func ReactivateVenue(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		accessToken, _ := c.Cookie("access_token")
+
+		venue, err := v.ReactivateVenue(c.Request.Context(), userId, venueId, claims.IsAdmin(), accessToken)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, "venue reactivated"))
+	}
+}
+
+// GetVenueStatusHistory lists a venue's lifecycle changes for its host or an admin
+func GetVenueStatusHistory(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		history, err := v.GetVenueStatusHistory(c.Request.Context(), userId, venueId, claims.IsAdmin())
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse(history, ""))
+	}
+}
+
+// GetVenueReviewQueue lists venues by lifecycle status, pending by default (admin only)
+func GetVenueReviewQueue(v *services.VenuesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("access denied"))
+			return
+		}
+
+		limit := c.DefaultQuery("limit", "20")
+		offset := c.DefaultQuery("offset", "0")
+		limitInt, err := strconv.Atoi(limit)
+		if err != nil || limitInt <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid limit parameter"))
+			return
+		}
+		offsetInt, err := strconv.Atoi(offset)
+		if err != nil || offsetInt < 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse("invalid offset parameter"))
+			return
+		}
+
+		venues, total, err := v.GetVenueReviewQueue(c.Request.Context(), c.Query("status"), offsetInt, limitInt)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		page := (offsetInt / limitInt) + 1
+		c.JSON(http.StatusOK, models.PaginatedResponse(venues, page, limitInt, total))
+	}
+}
+
+// ReviewVenue approves or rejects a pending venue (admin only)
+func ReviewVenue(v *services.VenuesService, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, userId, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, models.ErrorResponse("access denied"))
+			return
+		}
+		venueId, ok := parseVenueID(c)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		// The body is optional when approving
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
+		}
+
+		venue, err := v.ReviewVenue(c.Request.Context(), userId, venueId, approve, req.Reason)
+		if err != nil {
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
+			return
+		}
+
+		message := "Venue approved"
+		if !approve {
+			message = "Venue rejected"
+		}
+		c.JSON(http.StatusOK, models.SuccessResponse(venue, message))
+	}
+}
+
 func CreateManyVenues(v *services.VenuesService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var venues []*models.Venue
@@ -397,7 +1046,7 @@ func CreateManyVenues(v *services.VenuesService) gin.HandlerFunc {
 
 		createdVenues, err := v.CreateManyVenues(c.Request.Context(), venues, userId, accessToken)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
+			c.JSON(venueErrorStatus(err), models.ErrorResponse(err.Error()))
 			return
 		}
 
@@ -517,21 +1166,22 @@ func GetVenueViewHistory(v *services.VenuesService) gin.HandlerFunc {
 			return
 		}
 
-		// Get limit parameter (default 100)
-		limit := 100
-		if limitParam := c.Query("limit"); limitParam != "" {
-			if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
-				limit = l
-			}
+		page, ok := parsePageRequest(c, 100, false)
+		if !ok {
+			return
 		}
 
-		history, err := v.GetVenueViewHistory(c.Request.Context(), parsedVenueId, limit)
+		history, info, err := v.GetVenueViewHistory(c.Request.Context(), parsedVenueId, page)
 		if err != nil {
+			if errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
 			return
 		}
 
-		c.JSON(http.StatusOK, models.SuccessResponse(history, ""))
+		c.JSON(http.StatusOK, pageResponse(history, page, info))
 	}
 }
 
@@ -637,20 +1287,21 @@ func GetHostViewHistory(v *services.VenuesService) gin.HandlerFunc {
 			return
 		}
 
-		// Get limit parameter (default 100)
-		limit := 100
-		if limitParam := c.Query("limit"); limitParam != "" {
-			if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
-				limit = l
-			}
+		page, ok := parsePageRequest(c, 100, false)
+		if !ok {
+			return
 		}
 
-		history, err := v.GetHostViewHistory(c.Request.Context(), parsedHostId, limit)
+		history, info, err := v.GetHostViewHistory(c.Request.Context(), parsedHostId, page)
 		if err != nil {
+			if errors.Is(err, models.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse(err.Error()))
+				return
+			}
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(err.Error()))
 			return
 		}
 
-		c.JSON(http.StatusOK, models.SuccessResponse(history, ""))
+		c.JSON(http.StatusOK, pageResponse(history, page, info))
 	}
 }
@@ -0,0 +1,225 @@
+// Package imaging prepares uploaded photos for storage. Images are decoded from their real
+// content (never the client supplied type), turned upright according to their EXIF
+// orientation and re-encoded, which drops EXIF, GPS and any other embedded metadata.
+// Resized variants are generated alongside the cleaned original. Output is always JPEG, or
+// PNG for originals with transparency.
+//
+// WebP is read but not encoded: neither the standard library nor x/image has a WebP encoder,
+// so every backend stores JPEG variants. Media stores that transcode on delivery also give
+// each variant a WebP URL (storage.MediaStore.VariantURL); with the local store variants are
+// served as JPEG only.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("file is not a supported JPEG, PNG or WebP image")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+const (
+	// maxPixels guards against decompression bombs before any pixel data is decoded
+	maxPixels = 50_000_000
+	// maxOriginalSide bounds the longest side of the stored original
+	maxOriginalSide = 2560
+	originalQuality = 90
+	variantQuality  = 80
+)
+
+// decodable are the sniffed content types the package can read
+var decodable = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Spec describes a resized variant. With both sides set the image is scaled and centre
+// cropped to fill the box; a zero side leaves that dimension free. Images are never upscaled.
+type Spec struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// VenueSpecs are generated for every venue gallery image
+var VenueSpecs = []Spec{
+	{Name: "thumb", Width: 200, Height: 200},
+	{Name: "card", Width: 640, Height: 480},
+	{Name: "hero", Width: 1600, Height: 900},
+}
+
+// AvatarSpecs are generated for profile pictures
+var AvatarSpecs = []Spec{
+	{Name: "thumb", Width: 64, Height: 64},
+	{Name: "card", Width: 256, Height: 256},
+}
+
+// Rendition is an encoded image ready for upload
+type Rendition struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Result is a cleaned original and its variants, in the order of the specs
+type Result struct {
+	Original Rendition
+	Variants []Rendition
+}
+
+// Process reads an image, strips its metadata and renders the requested variants.
+// Variants are JPEG; the original stays PNG when it has transparency and is JPEG otherwise.
+func Process(r io.Reader, specs []Spec) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %v", err)
+	}
+	if !decodable[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	img := orient(toNRGBA(decoded), exifOrientation(data))
+
+	original := fit(img, maxOriginalSide, maxOriginalSide)
+	result := &Result{}
+	if original.Opaque() {
+		result.Original, err = encodeJPEG("original", original, originalQuality)
+	} else {
+		result.Original, err = encodePNG("original", original)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		var resized *image.NRGBA
+		if spec.Width > 0 && spec.Height > 0 {
+			resized = fill(img, spec.Width, spec.Height)
+		} else {
+			resized = fit(img, spec.Width, spec.Height)
+		}
+		variant, err := encodeJPEG(spec.Name, resized, variantQuality)
+		if err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, variant)
+	}
+
+	return result, nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Rect.Min == (image.Point{}) {
+		return img
+	}
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// fit scales img down to fit inside maxW x maxH; a zero bound is unconstrained
+func fit(img *image.NRGBA, maxW, maxH int) *image.NRGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	ratio := 1.0
+	if maxW > 0 && w > maxW {
+		ratio = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && float64(h)*ratio > float64(maxH) {
+		ratio = float64(maxH) / float64(h)
+	}
+	if ratio >= 1 {
+		return img
+	}
+	return scale(img, img.Rect, max(1, int(float64(w)*ratio+0.5)), max(1, int(float64(h)*ratio+0.5)))
+}
+
+// fill scales and centre crops img to exactly w x h, shrinking the box instead of upscaling
+func fill(img *image.NRGBA, w, h int) *image.NRGBA {
+	srcW, srcH := img.Rect.Dx(), img.Rect.Dy()
+	if srcW < w || srcH < h {
+		shrink := min(float64(srcW)/float64(w), float64(srcH)/float64(h))
+		w = max(1, int(float64(w)*shrink))
+		h = max(1, int(float64(h)*shrink))
+	}
+
+	// Largest source rectangle with the target aspect ratio, centred
+	cropW, cropH := srcW, srcW*h/w
+	if cropH > srcH {
+		cropW, cropH = srcH*w/h, srcH
+	}
+	x0 := (srcW - cropW) / 2
+	y0 := (srcH - cropH) / 2
+	return scale(img, image.Rect(x0, y0, x0+cropW, y0+cropH), w, h)
+}
+
+func scale(img *image.NRGBA, src image.Rectangle, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+func encodeJPEG(name string, img *image.NRGBA, quality int) (Rendition, error) {
+	var src image.Image = img
+	if !img.Opaque() {
+		// JPEG has no alpha channel, so transparent areas are flattened onto white
+		flat := image.NewRGBA(img.Rect)
+		draw.Draw(flat, flat.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Rect, img, img.Rect.Min, draw.Over)
+		src = flat
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: quality}); err != nil {
+		return Rendition{}, fmt.Errorf("failed to encode %s image: %v", name, err)
+	}
+	return Rendition{
+		Name:        name,
+		ContentType: "image/jpeg",
+		Data:        buf.Bytes(),
+		Width:       img.Rect.Dx(),
+		Height:      img.Rect.Dy(),
+	}, nil
+}
+
+func encodePNG(name string, img *image.NRGBA) (Rendition, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return Rendition{}, fmt.Errorf("failed to encode %s image: %v", name, err)
+	}
+	return Rendition{
+		Name:        name,
+		ContentType: "image/png",
+		Data:        buf.Bytes(),
+		Width:       img.Rect.Dx(),
+		Height:      img.Rect.Dy(),
+	}, nil
+}
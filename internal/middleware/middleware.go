@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/moderation"
 	"github.com/joshua-takyi/ww/internal/services"
 	"github.com/supabase-community/gotrue-go/types"
 	"github.com/supabase-community/supabase-go"
@@ -27,6 +28,21 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// Locale stores the request language on the request context so content moderation can
+// apply locale specific word lists. X-Locale takes precedence over Accept-Language.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := c.GetHeader("X-Locale")
+		if locale == "" {
+			locale = c.GetHeader("Accept-Language")
+		}
+		if locale != "" {
+			c.Request = c.Request.WithContext(moderation.WithLocale(c.Request.Context(), locale))
+		}
+		c.Next()
+	}
+}
+
 // StructuredLogger provides structured logging middleware
 func StructuredLogger(logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,259 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidAmenity = errors.New("invalid amenity")
+
+// AmenityType is the kind of value a venue records for an amenity
+type AmenityType string
+
+const (
+	// AmenityBoolean amenities are offered or not, e.g. wifi
+	AmenityBoolean AmenityType = "boolean"
+	// AmenityCount amenities record how many there are, e.g. parking spaces
+	AmenityCount AmenityType = "count"
+	// AmenityText amenities describe what is offered, e.g. the kind of backup power
+	AmenityText AmenityType = "text"
+)
+
+// maxAmenityTextLength bounds the value of text amenities
+const maxAmenityTextLength = 200
+
+// Amenity is an entry of the amenities catalogue. Venues record amenities by Key.
+type Amenity struct {
+	Key      string      `json:"key"`
+	Label    string      `json:"label"`
+	Type     AmenityType `json:"type"`
+	Category string      `json:"category"`
+	// Icon names an icon of the client's icon set
+	Icon string `json:"icon"`
+	// Unit labels count amenities, e.g. "spaces"
+	Unit string `json:"unit,omitempty"`
+}
+
+// AmenityCatalogue lists every amenity a venue can offer, grouped by category
+var AmenityCatalogue = []Amenity{
+	{Key: "wifi", Label: "Wi-Fi", Type: AmenityBoolean, Category: "connectivity", Icon: "wifi"},
+	{Key: "backup_power", Label: "Backup power", Type: AmenityText, Category: "connectivity", Icon: "zap"},
+
+	{Key: "parking", Label: "Parking", Type: AmenityCount, Category: "parking", Icon: "car", Unit: "spaces"},
+	{Key: "valet_parking", Label: "Valet parking", Type: AmenityBoolean, Category: "parking", Icon: "key"},
+
+	{Key: "air_conditioning", Label: "Air conditioning", Type: AmenityBoolean, Category: "comfort", Icon: "snowflake"},
+	{Key: "restrooms", Label: "Restrooms", Type: AmenityCount, Category: "comfort", Icon: "bath", Unit: "restrooms"},
+	{Key: "changing_rooms", Label: "Changing rooms", Type: AmenityCount, Category: "comfort", Icon: "shirt", Unit: "rooms"},
+
+	{Key: "kitchen", Label: "Kitchen", Type: AmenityBoolean, Category: "food_and_drink", Icon: "chef-hat"},
+	{Key: "bar", Label: "Bar", Type: AmenityBoolean, Category: "food_and_drink", Icon: "wine"},
+
+	{Key: "sound_system", Label: "Sound system", Type: AmenityBoolean, Category: "audio_visual", Icon: "speaker"},
+	{Key: "projector", Label: "Projector", Type: AmenityBoolean, Category: "audio_visual", Icon: "projector"},
+	{Key: "stage", Label: "Stage", Type: AmenityBoolean, Category: "audio_visual", Icon: "theater"},
+	{Key: "dance_floor", Label: "Dance floor", Type: AmenityBoolean, Category: "audio_visual", Icon: "music"},
+
+	{Key: "tables", Label: "Tables", Type: AmenityCount, Category: "furniture", Icon: "table", Unit: "tables"},
+	{Key: "chairs", Label: "Chairs", Type: AmenityCount, Category: "furniture", Icon: "armchair", Unit: "chairs"},
+
+	{Key: "wheelchair_access", Label: "Wheelchair access", Type: AmenityBoolean, Category: "accessibility", Icon: "accessibility"},
+	{Key: "elevator", Label: "Elevator", Type: AmenityBoolean, Category: "accessibility", Icon: "arrow-up-down"},
+
+	{Key: "outdoor_space", Label: "Outdoor space", Type: AmenityBoolean, Category: "outdoor", Icon: "trees"},
+	{Key: "pool", Label: "Pool", Type: AmenityBoolean, Category: "outdoor", Icon: "waves"},
+
+	{Key: "security", Label: "Security staff", Type: AmenityBoolean, Category: "safety", Icon: "shield"},
+	{Key: "cctv", Label: "CCTV", Type: AmenityBoolean, Category: "safety", Icon: "cctv"},
+}
+
+var amenitiesByKey = func() map[string]Amenity {
+	byKey := make(map[string]Amenity, len(AmenityCatalogue))
+	for _, amenity := range AmenityCatalogue {
+		byKey[amenity.Key] = amenity
+	}
+	return byKey
+}()
+
+// LookupAmenity finds a catalogue entry by key, ignoring case
+func LookupAmenity(key string) (Amenity, bool) {
+	amenity, ok := amenitiesByKey[strings.ToLower(strings.TrimSpace(key))]
+	return amenity, ok
+}
+
+// NormalizeAmenities checks venue amenities against the catalogue and returns them keyed in
+// lower case, with counts as integers and text trimmed. Empty text values are dropped.
+func NormalizeAmenities(amenities map[string]any) (map[string]any, error) {
+	if len(amenities) == 0 {
+		return amenities, nil
+	}
+
+	normalized := make(map[string]any, len(amenities))
+	for key, value := range amenities {
+		amenity, ok := LookupAmenity(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown amenity %q", ErrInvalidAmenity, key)
+		}
+		if value == nil {
+			continue
+		}
+
+		switch amenity.Type {
+		case AmenityBoolean:
+			offered, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidAmenity, amenity.Key)
+			}
+			normalized[amenity.Key] = offered
+		case AmenityCount:
+			n, ok := amenityCount(value)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s must be a whole number of at least 0", ErrInvalidAmenity, amenity.Key)
+			}
+			normalized[amenity.Key] = n
+		case AmenityText:
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s must be text", ErrInvalidAmenity, amenity.Key)
+			}
+			if text = strings.TrimSpace(text); text == "" {
+				continue
+			}
+			if utf8.RuneCountInString(text) > maxAmenityTextLength {
+				return nil, fmt.Errorf("%w: %s is too long", ErrInvalidAmenity, amenity.Key)
+			}
+			normalized[amenity.Key] = text
+		}
+	}
+	return normalized, nil
+}
+
+// AmenityOffered reports whether a recorded amenity value means the venue offers it: true,
+// a count above zero, or any text
+func AmenityOffered(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.TrimSpace(v) != ""
+	case nil:
+		return false
+	default:
+		n, ok := amenityCount(v)
+		return ok && n > 0
+	}
+}
+
+// amenityCount reads a count decoded from JSON, which arrives as float64, or set in Go
+func amenityCount(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, v >= 0
+	case int64:
+		return int(v), v >= 0
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v > math.MaxInt32 {
+			return 0, false
+		}
+		return int(v), true
+	}
+	return 0, false
+}
+
+// AmenityOp compares an amenity in a search filter
+type AmenityOp string
+
+const (
+	// AmenityOffers matches venues offering the amenity at all
+	AmenityOffers AmenityOp = ""
+	AmenityEq     AmenityOp = "="
+	AmenityGte    AmenityOp = ">="
+	AmenityLte    AmenityOp = "<="
+	AmenityGt     AmenityOp = ">"
+	AmenityLt     AmenityOp = "<"
+)
+
+// AmenityFilter matches venues by one amenity, e.g. "wifi", "wifi=true" or "parking>=10".
+// Text amenities only take "=", which matches case-insensitively anywhere in the text.
+type AmenityFilter struct {
+	Key   string    `json:"key"`
+	Op    AmenityOp `json:"op,omitempty"`
+	Value string    `json:"value,omitempty"`
+}
+
+// amenityOps are tried longest first so ">=" is not read as ">"
+var amenityOps = []AmenityOp{AmenityGte, AmenityLte, AmenityEq, AmenityGt, AmenityLt}
+
+// ParseAmenityFilter reads a filter written as key, key=value or key<op>count
+func ParseAmenityFilter(expr string) AmenityFilter {
+	for _, op := range amenityOps {
+		if key, value, ok := strings.Cut(expr, string(op)); ok {
+			return AmenityFilter{
+				Key:   strings.ToLower(strings.TrimSpace(key)),
+				Op:    op,
+				Value: strings.TrimSpace(value),
+			}
+		}
+	}
+	return AmenityFilter{Key: strings.ToLower(strings.TrimSpace(expr))}
+}
+
+// Validate checks the filter against the catalogue, wrapped in ErrInvalidVenueSearch
+func (f AmenityFilter) Validate() error {
+	amenity, ok := LookupAmenity(f.Key)
+	if !ok {
+		return fmt.Errorf("%w: unknown amenity %q", ErrInvalidVenueSearch, f.Key)
+	}
+	if f.Op == AmenityOffers {
+		return nil
+	}
+
+	switch amenity.Type {
+	case AmenityBoolean:
+		if f.Op != AmenityEq || (f.Value != "true" && f.Value != "false") {
+			return fmt.Errorf("%w: %s takes =true or =false", ErrInvalidVenueSearch, f.Key)
+		}
+	case AmenityCount:
+		if n, err := strconv.Atoi(f.Value); err != nil || n < 0 {
+			return fmt.Errorf("%w: %s must be compared with a whole number", ErrInvalidVenueSearch, f.Key)
+		}
+	case AmenityText:
+		if f.Op != AmenityEq {
+			return fmt.Errorf("%w: %s only takes =", ErrInvalidVenueSearch, f.Key)
+		}
+		if err := validateSearchTerm(f.Key, f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// condition renders a validated filter as a PostgREST condition on the amenities column
+func (f AmenityFilter) condition() string {
+	amenity, _ := LookupAmenity(f.Key)
+	number := "amenities->" + amenity.Key
+	text := "amenities->>" + amenity.Key
+
+	switch {
+	case f.Op == AmenityOffers && amenity.Type == AmenityBoolean:
+		return number + ".eq.true"
+	case f.Op == AmenityOffers && amenity.Type == AmenityCount:
+		return number + ".gt.0"
+	case f.Op == AmenityOffers:
+		return text + ".not.is.null"
+	case amenity.Type == AmenityText:
+		return text + ".ilike.*" + f.Value + "*"
+	case amenity.Type == AmenityBoolean && f.Value == "false":
+		// Venues that never recorded the amenity do not offer it either
+		return "or(" + number + ".is.null," + number + ".eq.false)"
+	}
+
+	operators := map[AmenityOp]string{
+		AmenityEq: "eq", AmenityGte: "gte", AmenityLte: "lte", AmenityGt: "gt", AmenityLt: "lt",
+	}
+	return number + "." + operators[f.Op] + "." + f.Value
+}
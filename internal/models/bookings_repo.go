@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+)
+
+const (
+	BookingsTable = "bookings"
+
+	BookingStatusPending   = "pending"
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCanceled  = "canceled"
+	BookingStatusCompleted = "completed"
+)
+
+var ErrBookingNotFound = errors.New("booking not found")
+
+type BookingsRepo interface {
+	GetBookingByID(ctx context.Context, id uuid.UUID, accessToken string) (*Bookings, error)
+	ListBlockingBookings(ctx context.Context, venueIds []uuid.UUID, from, to time.Time) ([]Bookings, error)
+}
+
+func (su *SupabaseRepo) GetBookingByID(ctx context.Context, id uuid.UUID, accessToken string) (*Bookings, error) {
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("invalid booking ID")
+	}
+
+	client := su.getClientWithAuth(accessToken)
+	data, _, err := client.From(BookingsTable).Select("*", "exact", false).Eq("id", id.String()).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %v", err)
+	}
+
+	var bookings []Bookings
+	if err := json.Unmarshal(data, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal booking: %v", err)
+	}
+	if len(bookings) == 0 {
+		return nil, ErrBookingNotFound
+	}
+
+	return &bookings[0], nil
+}
+
+const (
+	// bookingVenueBatch bounds how many venue IDs one bookings query filters on
+	bookingVenueBatch = 200
+	// bookingPage bounds the bookings read per request, below the PostgREST max-rows cap
+	bookingPage = 500
+)
+
+// ListBlockingBookings lists the bookings of the given venues that hold a slot overlapping
+// [from, to): confirmed ones, and pending ones while their payment goes through. Only the
+// venue and times are set. Bookings are read a batch of venues and a page at a time until a
+// page comes back empty, so the server's row cap cannot drop any. They are read with the service
+// role, since row level security hides other guests' bookings from the anon key; without it
+// ErrServiceRoleUnavailable is returned rather than every venue looking free.
+func (su *SupabaseRepo) ListBlockingBookings(ctx context.Context, venueIds []uuid.UUID, from, to time.Time) ([]Bookings, error) {
+	client, err := su.getServiceClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var bookings []Bookings
+	for start := 0; start < len(venueIds); start += bookingVenueBatch {
+		batch := venueIds[start:min(start+bookingVenueBatch, len(venueIds))]
+		ids := make([]string, len(batch))
+		for i, id := range batch {
+			ids[i] = id.String()
+		}
+
+		for offset := 0; ; {
+			data, _, err := client.From(BookingsTable).Select("id,venues_id,start_time,end_time", "", false).
+				In("venues_id", ids).
+				In("status", []string{BookingStatusPending, BookingStatusConfirmed}).
+				Lt("start_time", to.UTC().Format(time.RFC3339)).
+				Gt("end_time", from.UTC().Format(time.RFC3339)).
+				Order("id", &postgrest.OrderOpts{Ascending: true}).
+				Range(offset, offset+bookingPage-1, "").Execute()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bookings: %v", err)
+			}
+
+			var page []Bookings
+			if err := json.Unmarshal(data, &page); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal bookings: %v", err)
+			}
+			if len(page) == 0 {
+				break
+			}
+			bookings = append(bookings, page...)
+			offset += len(page)
+		}
+	}
+	return bookings, nil
+}
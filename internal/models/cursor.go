@@ -0,0 +1,149 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a position in a paginated list, handed to clients as an opaque token.
+// Lists ordered by the database page by Key, the sort values of the item next to the
+// position; lists ranked in memory page by Offset.
+type Cursor struct {
+	// Scope ties the cursor to the list it was issued for
+	Scope  string   `json:"s"`
+	Key    []string `json:"k,omitempty"`
+	Offset int      `json:"o,omitempty"`
+	// Backward cursors return the items before Key instead of those after it
+	Backward bool `json:"b,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token issued for scope. An empty token gives a nil cursor.
+func DecodeCursor(token, scope string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Scope != scope {
+		return nil, fmt.Errorf("%w: it belongs to another list or query", ErrInvalidCursor)
+	}
+	if c.Offset < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// PageRequest asks for one page of a list
+type PageRequest struct {
+	// Cursor is a token from a previous page; the first page is returned without one
+	Cursor string
+	// Offset is honoured when there is no cursor, for clients that still page by offset
+	Offset int
+	Limit  int
+	// WithTotal asks for the size of the whole list, which costs an extra count
+	WithTotal bool
+}
+
+// PageInfo locates a page within its list
+type PageInfo struct {
+	NextCursor string
+	PrevCursor string
+	// Total is only counted when the request asked for it
+	Total int
+	// Truncated is set when a sort ranked in memory only ranked part of the matches, so
+	// some of them are missing from the list
+	Truncated bool
+}
+
+// OffsetPage builds the page info of a list paged by offset
+func OffsetPage(scope string, offset, limit int, hasMore bool) PageInfo {
+	var info PageInfo
+	if hasMore {
+		info.NextCursor = Cursor{Scope: scope, Offset: offset + limit}.Encode()
+	}
+	if offset > 0 {
+		info.PrevCursor = Cursor{Scope: scope, Offset: max(offset-limit, 0)}.Encode()
+	}
+	return info
+}
+
+// KeysetPage builds the page info of a list paged by key, given the keys of the first and
+// last items returned for cursor
+func KeysetPage(scope string, cursor *Cursor, first, last []string, hasMore bool) PageInfo {
+	var info PageInfo
+	if first == nil {
+		return info
+	}
+	backward := cursor != nil && cursor.Backward
+	if hasMore || backward {
+		info.NextCursor = Cursor{Scope: scope, Key: last}.Encode()
+	}
+	if cursor != nil && (!backward || hasMore) {
+		info.PrevCursor = Cursor{Scope: scope, Key: first, Backward: true}.Encode()
+	}
+	return info
+}
+
+// TrimPage drops the extra item fetched beyond limit to tell whether more items follow.
+// Backward pages are fetched in reverse, so their extra item is the first one.
+func TrimPage[T any](items []T, limit int, backward bool) ([]T, bool) {
+	if len(items) <= limit {
+		return items, false
+	}
+	if backward {
+		return items[len(items)-limit:], true
+	}
+	return items[:limit], true
+}
+
+// timeKey is the cursor key of an item in a list sorted newest first
+func timeKey(t time.Time, id string) []string {
+	return []string{t.UTC().Format(time.RFC3339Nano), id}
+}
+
+// timeKeyFilter pages a collection sorted newest first by timeField, then idField. It returns
+// the filter selecting the items past cursor and the direction to sort them in; parseID
+// converts the ID half of the key to its stored type.
+func timeKeyFilter(cursor *Cursor, timeField, idField string, parseID func(string) (interface{}, error)) (bson.M, int, error) {
+	if cursor == nil {
+		return bson.M{}, -1, nil
+	}
+	if len(cursor.Key) != 2 {
+		return nil, 0, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, cursor.Key[0])
+	if err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+	id, err := parseID(cursor.Key[1])
+	if err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	op, direction := "$lt", -1
+	if cursor.Backward {
+		op, direction = "$gt", 1
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{timeField: bson.M{op: t}},
+		bson.M{timeField: t, idField: bson.M{op: id}},
+	}}, direction, nil
+}
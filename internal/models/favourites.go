@@ -3,11 +3,13 @@ package models
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -35,6 +37,9 @@ type FavouriteRepo interface {
 	RemoveFromFavourites(ctx context.Context, userId uuid.UUID, itemId string) error
 	// GetFavById(ctx context.Context, id primitive.ObjectID) (*Favourite, error)
 	GetFavouritesByUserID(ctx context.Context, userId uuid.UUID) ([]*Favourite, error)
+	ListFavouriteItems(ctx context.Context, userId uuid.UUID, cursor *Cursor, limit int) ([]FavouriteItem, error)
+	CountFavouriteItems(ctx context.Context, userId uuid.UUID) (int, error)
+	RemoveItemFromAllFavourites(ctx context.Context, itemId string) error
 }
 
 func (f *Favourite) BeforeCreate() error {
@@ -129,6 +134,75 @@ func (mdb *MongodbRepo) GetFavouritesByUserID(ctx context.Context, userId uuid.U
 	return favourites, nil
 }
 
+// ListFavouriteItems returns up to limit of a user's favourite items past cursor, most recently added first
+func (mdb *MongodbRepo) ListFavouriteItems(ctx context.Context, userId uuid.UUID, cursor *Cursor, limit int) ([]FavouriteItem, error) {
+	col, err := mdb.GetCollection(ctx, FavouriteDbName, FavouriteColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	keyFilter, direction, err := timeKeyFilter(cursor, "added_at", "item_id", func(id string) (interface{}, error) {
+		return id, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are kept in a map keyed by item ID, so they are unwound into documents to be paged
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userId}}},
+		{{Key: "$project", Value: bson.M{"items": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$items", bson.M{}}}}}}},
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$items.v"}}},
+		{{Key: "$match", Value: keyFilter}},
+		{{Key: "$sort", Value: bson.D{{Key: "added_at", Value: direction}, {Key: "item_id", Value: direction}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursorRes, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error listing favourite items: %v", err)
+	}
+	defer cursorRes.Close(ctx)
+
+	var items []FavouriteItem
+	if err := cursorRes.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("error decoding favourite items: %v", err)
+	}
+	// Backward pages are fetched oldest first
+	if direction > 0 {
+		slices.Reverse(items)
+	}
+
+	return items, nil
+}
+
+func (mdb *MongodbRepo) CountFavouriteItems(ctx context.Context, userId uuid.UUID) (int, error) {
+	col, err := mdb.GetCollection(ctx, FavouriteDbName, FavouriteColName)
+	if err != nil {
+		return 0, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var result struct {
+		Count int `bson:"count"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{
+		"count": bson.M{"$size": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$items", bson.M{}}}}},
+	})
+	if err := col.FindOne(ctx, bson.M{"user_id": userId}, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error counting favourite items: %v", err)
+	}
+
+	return result.Count, nil
+}
+
+// FavouriteCursorKey is the cursor key of an item in a favourites list
+func FavouriteCursorKey(item FavouriteItem) []string {
+	return timeKey(item.AddedAt, item.ItemID)
+}
+
 // func (mdb *MongodbRepo) GetFavById(ctx context.Context, id primitive.ObjectID) (*Favourite, error) {
 // 	col, err := mdb.GetCollection(ctx, FavouriteDbName, FavouriteColName)
 // 	if err != nil {
@@ -143,3 +217,25 @@ func (mdb *MongodbRepo) GetFavouritesByUserID(ctx context.Context, userId uuid.U
 
 // 	return &fav, nil
 // }
+
+// RemoveItemFromAllFavourites drops an item from every user's favourites, e.g. when a venue is purged
+func (mdb *MongodbRepo) RemoveItemFromAllFavourites(ctx context.Context, itemId string) error {
+	col, err := mdb.GetCollection(ctx, FavouriteDbName, FavouriteColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	key := fmt.Sprintf("items.%s", itemId)
+	_, err = col.UpdateMany(ctx,
+		bson.M{key: bson.M{"$exists": true}},
+		bson.M{
+			"$unset": bson.M{key: ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error removing favourite item: %v", err)
+	}
+
+	return nil
+}
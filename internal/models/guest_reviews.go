@@ -0,0 +1,261 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	GuestReviewDbName  = "bashbay"
+	GuestReviewColName = "guest_reviews"
+)
+
+var ErrGuestReviewAlreadyExists = errors.New("a guest review already exists for this booking")
+
+// GuestReview is a host's rating of a guest after a completed booking.
+// It stays hidden from the guest until BlindUntil (double-blind publishing).
+type GuestReview struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+
+	BookingID uuid.UUID `bson:"booking_id" json:"booking_id"`
+	VenueID   uuid.UUID `bson:"venue_id" json:"venue_id"`
+	HostID    uuid.UUID `bson:"host_id" json:"host_id"`
+	GuestID   uuid.UUID `bson:"guest_id" json:"guest_id"`
+
+	Cleanliness   int    `bson:"cleanliness" json:"cleanliness" validate:"required,min=1,max=5"`
+	Communication int    `bson:"communication" json:"communication" validate:"required,min=1,max=5"`
+	RuleFollowing int    `bson:"rule_following" json:"rule_following" validate:"required,min=1,max=5"`
+	Comment       string `bson:"comment" json:"comment"`
+
+	BlindUntil time.Time `bson:"blind_until" json:"blind_until"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// GuestReputation summarises the published reviews hosts have written about a guest
+type GuestReputation struct {
+	GuestID          uuid.UUID      `json:"guest_id"`
+	ReviewCount      int            `json:"review_count"`
+	AvgCleanliness   float64        `json:"avg_cleanliness"`
+	AvgCommunication float64        `json:"avg_communication"`
+	AvgRuleFollowing float64        `json:"avg_rule_following"`
+	Overall          float64        `json:"overall"`
+	Recent           []*GuestReview `json:"recent"`
+}
+
+type GuestReviewsRepo interface {
+	CreateGuestReview(ctx context.Context, review *GuestReview) (*GuestReview, error)
+	GetGuestReviewByBooking(ctx context.Context, bookingId uuid.UUID) (*GuestReview, error)
+	GetGuestReviewsByGuest(ctx context.Context, guestId uuid.UUID, revealedOnly bool) ([]*GuestReview, error)
+	GetGuestReviewsByHost(ctx context.Context, hostId uuid.UUID) ([]*GuestReview, error)
+	RevealGuestReview(ctx context.Context, bookingId uuid.UUID, at time.Time) error
+	GetGuestReputation(ctx context.Context, guestId uuid.UUID, recent int) (*GuestReputation, error)
+	EnsureGuestReviewIndexes(ctx context.Context) error
+}
+
+func (g *GuestReview) Sanitize() {
+	g.Comment = helpers.StringTrim(g.Comment)
+}
+
+// IsRevealed reports whether the review is visible to the guest at time now
+func (g *GuestReview) IsRevealed(now time.Time) bool {
+	return !now.Before(g.BlindUntil)
+}
+
+// revealedFilter matches documents whose blind period has ended
+func revealedFilter(now time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"blind_until": bson.M{"$exists": false}},
+		bson.M{"blind_until": bson.M{"$lte": now}},
+	}}
+}
+
+func (mdb *MongodbRepo) EnsureGuestReviewIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	indexes := []mongo.IndexModel{
+		// One guest review per booking
+		{
+			Keys: bson.D{{Key: "booking_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetName("booking_id_unique"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "guest_id", Value: 1},
+				{Key: "blind_until", Value: 1},
+			},
+			Options: options.Index().SetName("guest_blind_until_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "host_id", Value: 1}},
+			Options: options.Index().SetName("host_id_idx"),
+		},
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) CreateGuestReview(ctx context.Context, review *GuestReview) (*GuestReview, error) {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+	if _, err := col.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrGuestReviewAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to insert guest review: %v", err)
+	}
+
+	return review, nil
+}
+
+// GetGuestReviewByBooking returns the host's review for a booking, or nil if none exists yet
+func (mdb *MongodbRepo) GetGuestReviewByBooking(ctx context.Context, bookingId uuid.UUID) (*GuestReview, error) {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var review GuestReview
+	if err := col.FindOne(ctx, bson.M{"booking_id": bookingId}).Decode(&review); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding guest review: %v", err)
+	}
+
+	return &review, nil
+}
+
+func (mdb *MongodbRepo) GetGuestReviewsByGuest(ctx context.Context, guestId uuid.UUID, revealedOnly bool) ([]*GuestReview, error) {
+	filter := bson.M{"guest_id": guestId}
+	if revealedOnly {
+		filter = bson.M{"$and": bson.A{filter, revealedFilter(time.Now())}}
+	}
+	return mdb.findGuestReviews(ctx, filter, 0)
+}
+
+func (mdb *MongodbRepo) GetGuestReviewsByHost(ctx context.Context, hostId uuid.UUID) ([]*GuestReview, error) {
+	return mdb.findGuestReviews(ctx, bson.M{"host_id": hostId}, 0)
+}
+
+func (mdb *MongodbRepo) findGuestReviews(ctx context.Context, filter bson.M, limit int) ([]*GuestReview, error) {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	cursor, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding guest reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*GuestReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("error decoding guest reviews: %v", err)
+	}
+
+	return reviews, nil
+}
+
+// RevealGuestReview ends the blind period of a booking's guest review early
+func (mdb *MongodbRepo) RevealGuestReview(ctx context.Context, bookingId uuid.UUID, at time.Time) error {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.UpdateOne(ctx,
+		bson.M{"booking_id": bookingId, "blind_until": bson.M{"$gt": at}},
+		bson.M{"$set": bson.M{"blind_until": at, "updated_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("error revealing guest review: %v", err)
+	}
+
+	return nil
+}
+
+// GetGuestReputation averages the revealed reviews of a guest and includes the most recent ones
+func (mdb *MongodbRepo) GetGuestReputation(ctx context.Context, guestId uuid.UUID, recent int) (*GuestReputation, error) {
+	col, err := mdb.GetCollection(ctx, GuestReviewDbName, GuestReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	match := bson.M{"$and": bson.A{bson.M{"guest_id": guestId}, revealedFilter(time.Now())}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            nil,
+			"count":          bson.M{"$sum": 1},
+			"cleanliness":    bson.M{"$avg": "$cleanliness"},
+			"communication":  bson.M{"$avg": "$communication"},
+			"rule_following": bson.M{"$avg": "$rule_following"},
+		}}},
+	}
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating guest reputation: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Count         int     `bson:"count"`
+		Cleanliness   float64 `bson:"cleanliness"`
+		Communication float64 `bson:"communication"`
+		RuleFollowing float64 `bson:"rule_following"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("error decoding guest reputation: %v", err)
+	}
+
+	rep := &GuestReputation{GuestID: guestId, Recent: []*GuestReview{}}
+	if len(rows) > 0 {
+		round := func(f float64) float64 { return math.Round(f*100) / 100 }
+		rep.ReviewCount = rows[0].Count
+		rep.AvgCleanliness = round(rows[0].Cleanliness)
+		rep.AvgCommunication = round(rows[0].Communication)
+		rep.AvgRuleFollowing = round(rows[0].RuleFollowing)
+		rep.Overall = round((rows[0].Cleanliness + rows[0].Communication + rows[0].RuleFollowing) / 3)
+	}
+
+	if recent > 0 && rep.ReviewCount > 0 {
+		reviews, err := mdb.findGuestReviews(ctx, match, recent)
+		if err != nil {
+			return nil, err
+		}
+		rep.Recent = reviews
+	}
+
+	return rep, nil
+}
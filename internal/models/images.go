@@ -0,0 +1,13 @@
+package models
+
+// ImageVariant is one resized rendition of an uploaded image
+type ImageVariant struct {
+	URL string `json:"url"`
+	// WebPURL serves the same rendition as WebP, when the media store can
+	WebPURL string `json:"webp_url,omitempty"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+}
+
+// ImageVariants maps a size name ("thumb", "card", "hero") to its rendition
+type ImageVariants map[string]ImageVariant
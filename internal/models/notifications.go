@@ -0,0 +1,177 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	NotificationsDbName  = "bashbay"
+	NotificationsColName = "notifications"
+)
+
+// Notification types
+const (
+	NotificationSavedSearchMatch = "saved_search_match"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Notification is a message shown to a user in their notification list
+type Notification struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID uuid.UUID          `bson:"user_id" json:"user_id"`
+	Type   string             `bson:"type" json:"type"`
+	Title  string             `bson:"title" json:"title"`
+	Body   string             `bson:"body" json:"body"`
+	// Data holds what the notification is about, e.g. the venue to open
+	Data map[string]string `bson:"data,omitempty" json:"data,omitempty"`
+	// DedupeKey is unique; a notification whose key was already used is not sent again
+	DedupeKey string     `bson:"dedupe_key,omitempty" json:"-"`
+	ReadAt    *time.Time `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+}
+
+type NotificationsRepo interface {
+	CreateNotification(ctx context.Context, notification *Notification) (bool, error)
+	ListNotifications(ctx context.Context, userId uuid.UUID, cursor *Cursor, limit int) ([]*Notification, error)
+	CountNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool) (int, error)
+	MarkNotificationRead(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error
+	EnsureNotificationIndexes(ctx context.Context) error
+}
+
+func (mdb *MongodbRepo) EnsureNotificationIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, NotificationsDbName, NotificationsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_created_idx"),
+		},
+		{
+			// Partial so notifications without a key never clash
+			Keys: bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("dedupe_key_unique").
+				SetPartialFilterExpression(bson.M{"dedupe_key": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+// CreateNotification stores a notification and reports whether it was new. A notification
+// whose dedupe key was already used is dropped without an error.
+func (mdb *MongodbRepo) CreateNotification(ctx context.Context, notification *Notification) (bool, error) {
+	col, err := mdb.GetCollection(ctx, NotificationsDbName, NotificationsColName)
+	if err != nil {
+		return false, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	if _, err := col.InsertOne(ctx, notification); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error creating notification: %v", err)
+	}
+
+	return true, nil
+}
+
+// ListNotifications returns up to limit of a user's notifications past cursor, newest first
+func (mdb *MongodbRepo) ListNotifications(ctx context.Context, userId uuid.UUID, cursor *Cursor, limit int) ([]*Notification, error) {
+	col, err := mdb.GetCollection(ctx, NotificationsDbName, NotificationsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	keyFilter, direction, err := timeKeyFilter(cursor, "created_at", "_id", func(id string) (interface{}, error) {
+		return primitive.ObjectIDFromHex(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(limit))
+
+	cursorRes, err := col.Find(ctx, bson.M{"$and": bson.A{bson.M{"user_id": userId}, keyFilter}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding notifications: %v", err)
+	}
+	defer cursorRes.Close(ctx)
+
+	notifications := []*Notification{}
+	if err := cursorRes.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("error decoding notifications: %v", err)
+	}
+	// Backward pages are fetched oldest first
+	if direction > 0 {
+		slices.Reverse(notifications)
+	}
+
+	return notifications, nil
+}
+
+func (mdb *MongodbRepo) CountNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool) (int, error) {
+	col, err := mdb.GetCollection(ctx, NotificationsDbName, NotificationsColName)
+	if err != nil {
+		return 0, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{"user_id": userId}
+	if unreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("error counting notifications: %v", err)
+	}
+
+	return int(count), nil
+}
+
+// MarkNotificationRead marks one of a user's notifications read; reading it again keeps the
+// first read time
+func (mdb *MongodbRepo) MarkNotificationRead(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error {
+	col, err := mdb.GetCollection(ctx, NotificationsDbName, NotificationsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	res, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userId},
+		bson.A{bson.M{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("error marking notification read: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// NotificationCursorKey is the cursor key of a notification in a notification list
+func NotificationCursorKey(n *Notification) []string {
+	return timeKey(n.CreatedAt, n.ID.Hex())
+}
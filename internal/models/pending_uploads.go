@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	PendingUploadsDbName  = "bashbay"
+	PendingUploadsColName = "pending_uploads"
+)
+
+// Upload purposes
+const (
+	UploadPurposeVenueImage = "venue_image"
+)
+
+// PendingUpload is a direct upload ticket that has been issued but not yet confirmed.
+// Expired records are garbage collected together with whatever was uploaded for them.
+type PendingUpload struct {
+	PublicID  string    `bson:"public_id" json:"public_id"`
+	OwnerID   uuid.UUID `bson:"owner_id" json:"owner_id"`
+	Purpose   string    `bson:"purpose" json:"purpose"`
+	TargetID  uuid.UUID `bson:"target_id" json:"target_id"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+type PendingUploadsRepo interface {
+	CreatePendingUploads(ctx context.Context, uploads []*PendingUpload) error
+	GetPendingUploads(ctx context.Context, publicIDs []string) ([]*PendingUpload, error)
+	ListExpiredPendingUploads(ctx context.Context, before time.Time, limit int) ([]*PendingUpload, error)
+	DeletePendingUploads(ctx context.Context, publicIDs []string) error
+	EnsurePendingUploadIndexes(ctx context.Context) error
+}
+
+func (mdb *MongodbRepo) EnsurePendingUploadIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, PendingUploadsDbName, PendingUploadsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "public_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("public_id_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_idx"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) CreatePendingUploads(ctx context.Context, uploads []*PendingUpload) error {
+	if len(uploads) == 0 {
+		return nil
+	}
+	col, err := mdb.GetCollection(ctx, PendingUploadsDbName, PendingUploadsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	docs := make([]interface{}, len(uploads))
+	for i, upload := range uploads {
+		docs[i] = upload
+	}
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("error creating pending uploads: %v", err)
+	}
+
+	return nil
+}
+
+// GetPendingUploads returns the records found for publicIDs; unknown IDs are left out
+func (mdb *MongodbRepo) GetPendingUploads(ctx context.Context, publicIDs []string) ([]*PendingUpload, error) {
+	col, err := mdb.GetCollection(ctx, PendingUploadsDbName, PendingUploadsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	cursor, err := col.Find(ctx, bson.M{"public_id": bson.M{"$in": publicIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding pending uploads: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var uploads []*PendingUpload
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, fmt.Errorf("error decoding pending uploads: %v", err)
+	}
+
+	return uploads, nil
+}
+
+// ListExpiredPendingUploads returns up to limit records that expired before the given time, oldest first
+func (mdb *MongodbRepo) ListExpiredPendingUploads(ctx context.Context, before time.Time, limit int) ([]*PendingUpload, error) {
+	col, err := mdb.GetCollection(ctx, PendingUploadsDbName, PendingUploadsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, bson.M{"expires_at": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding expired uploads: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var uploads []*PendingUpload
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, fmt.Errorf("error decoding expired uploads: %v", err)
+	}
+
+	return uploads, nil
+}
+
+func (mdb *MongodbRepo) DeletePendingUploads(ctx context.Context, publicIDs []string) error {
+	if len(publicIDs) == 0 {
+		return nil
+	}
+	col, err := mdb.GetCollection(ctx, PendingUploadsDbName, PendingUploadsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.DeleteMany(ctx, bson.M{"public_id": bson.M{"$in": publicIDs}}); err != nil {
+		return fmt.Errorf("error deleting pending uploads: %v", err)
+	}
+
+	return nil
+}
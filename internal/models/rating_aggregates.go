@@ -0,0 +1,312 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	RatingAggregatesDbName  = "bashbay"
+	RatingAggregatesColName = "venue_rating_aggregates"
+)
+
+// VenueRatingAggregate is the maintained rating summary for a single venue
+type VenueRatingAggregate struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	VenueID       uuid.UUID          `bson:"venue_id" json:"venue_id"`
+	ReviewCount   int                `bson:"review_count" json:"review_count"`
+	RatingSum     int                `bson:"rating_sum" json:"-"`
+	AverageRating float64            `bson:"average_rating" json:"average_rating"`
+	Histogram     map[string]int     `bson:"histogram" json:"histogram"`           // star ("1".."5") -> number of reviews
+	FeatureCounts map[string]int     `bson:"feature_counts" json:"feature_counts"` // liked feature -> number of reviews mentioning it
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// RatingDelta describes how a single review change moves a venue's aggregate
+type RatingDelta struct {
+	Count     int
+	Sum       int
+	Histogram map[int]int
+	Features  map[string]int
+}
+
+type RatingAggregatesRepo interface {
+	ApplyRatingDelta(ctx context.Context, venueId uuid.UUID, delta RatingDelta) (*VenueRatingAggregate, error)
+	GetRatingAggregate(ctx context.Context, venueId uuid.UUID) (*VenueRatingAggregate, error)
+	GetRatingAggregates(ctx context.Context, venueIds []uuid.UUID) ([]*VenueRatingAggregate, error)
+	ListRatingAggregateVenueIDs(ctx context.Context) ([]uuid.UUID, error)
+	ReplaceRatingAggregates(ctx context.Context, aggregates []*VenueRatingAggregate) error
+	DeleteRatingAggregate(ctx context.Context, venueId uuid.UUID) error
+}
+
+// CountsTowardsRating reports whether a review contributes to its venue's public aggregate.
+// Blind reviews only count once they are marked revealed, as the aggregate is public.
+func (r *VenueReview) CountsTowardsRating() bool {
+	return r != nil && r.Status == ReviewStatusApproved && (r.BlindUntil == nil || r.Revealed)
+}
+
+// NewRatingDelta returns the change in aggregate caused by a review moving from before to after.
+// Either side may be nil (create/delete) or not counted (e.g. not yet approved).
+func NewRatingDelta(before, after *VenueReview) RatingDelta {
+	delta := RatingDelta{
+		Histogram: map[int]int{},
+		Features:  map[string]int{},
+	}
+	apply := func(r *VenueReview, sign int) {
+		if !r.CountsTowardsRating() {
+			return
+		}
+		delta.Count += sign
+		delta.Sum += sign * r.Rating
+		delta.Histogram[r.Rating] += sign
+		for _, f := range r.LikedFeatures {
+			if key := featureKey(f); key != "" {
+				delta.Features[key] += sign
+			}
+		}
+	}
+	apply(before, -1)
+	apply(after, 1)
+	return delta
+}
+
+// IsZero reports whether applying the delta would leave the aggregate unchanged
+func (d RatingDelta) IsZero() bool {
+	if d.Count != 0 || d.Sum != 0 {
+		return false
+	}
+	for _, v := range d.Histogram {
+		if v != 0 {
+			return false
+		}
+	}
+	for _, v := range d.Features {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add accumulates a review into an aggregate being rebuilt from scratch
+func (a *VenueRatingAggregate) Add(r *VenueReview) {
+	if !r.CountsTowardsRating() {
+		return
+	}
+	if a.Histogram == nil {
+		a.Histogram = map[string]int{}
+	}
+	if a.FeatureCounts == nil {
+		a.FeatureCounts = map[string]int{}
+	}
+	a.ReviewCount++
+	a.RatingSum += r.Rating
+	a.Histogram[strconv.Itoa(r.Rating)]++
+	for _, f := range r.LikedFeatures {
+		if key := featureKey(f); key != "" {
+			a.FeatureCounts[key]++
+		}
+	}
+	a.AverageRating = averageRating(a.RatingSum, a.ReviewCount)
+}
+
+func averageRating(sum, count int) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return math.Round(float64(sum)/float64(count)*100) / 100
+}
+
+// featureKey normalises a liked feature so it can be used as a MongoDB field name
+func featureKey(feature string) string {
+	key := strings.TrimSpace(feature)
+	key = strings.ReplaceAll(key, ".", " ")
+	key = strings.TrimLeft(key, "$")
+	return strings.TrimSpace(key)
+}
+
+// incField builds an aggregation expression adding n to a (possibly missing) field
+func incField(path string, n int) bson.M {
+	return bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + path, 0}}, n}}
+}
+
+// ApplyRatingDelta atomically applies a delta to a venue's aggregate, creating it if needed,
+// and recomputes the average in the same update.
+func (mdb *MongodbRepo) ApplyRatingDelta(ctx context.Context, venueId uuid.UUID, delta RatingDelta) (*VenueRatingAggregate, error) {
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	set := bson.M{
+		"venue_id":     venueId,
+		"review_count": incField("review_count", delta.Count),
+		"rating_sum":   incField("rating_sum", delta.Sum),
+		"updated_at":   time.Now(),
+	}
+	for star, n := range delta.Histogram {
+		if n != 0 {
+			path := fmt.Sprintf("histogram.%d", star)
+			set[path] = incField(path, n)
+		}
+	}
+	for feature, n := range delta.Features {
+		if n != 0 {
+			path := "feature_counts." + feature
+			set[path] = incField(path, n)
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: set}},
+		{{Key: "$set", Value: bson.M{
+			"average_rating": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$review_count", 0}},
+				bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$rating_sum", "$review_count"}}, 2}},
+				0,
+			}},
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var result VenueRatingAggregate
+	if err := col.FindOneAndUpdate(ctx, bson.M{"venue_id": venueId}, pipeline, opts).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error updating rating aggregate: %v", err)
+	}
+
+	return &result, nil
+}
+
+// GetRatingAggregate returns a venue's aggregate, or an empty one if the venue has no reviews
+func (mdb *MongodbRepo) GetRatingAggregate(ctx context.Context, venueId uuid.UUID) (*VenueRatingAggregate, error) {
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var result VenueRatingAggregate
+	if err := col.FindOne(ctx, bson.M{"venue_id": venueId}).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &VenueRatingAggregate{
+				VenueID:       venueId,
+				Histogram:     map[string]int{},
+				FeatureCounts: map[string]int{},
+			}, nil
+		}
+		return nil, fmt.Errorf("error finding rating aggregate: %v", err)
+	}
+
+	return &result, nil
+}
+
+// GetRatingAggregates returns the aggregates of the given venues; venues without reviews are left out
+func (mdb *MongodbRepo) GetRatingAggregates(ctx context.Context, venueIds []uuid.UUID) ([]*VenueRatingAggregate, error) {
+	if len(venueIds) == 0 {
+		return nil, nil
+	}
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	cursor, err := col.Find(ctx, bson.M{"venue_id": bson.M{"$in": venueIds}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding rating aggregates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var aggregates []*VenueRatingAggregate
+	if err := cursor.All(ctx, &aggregates); err != nil {
+		return nil, fmt.Errorf("error decoding rating aggregates: %v", err)
+	}
+
+	return aggregates, nil
+}
+
+func (mdb *MongodbRepo) ListRatingAggregateVenueIDs(ctx context.Context) ([]uuid.UUID, error) {
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetProjection(bson.M{"venue_id": 1})
+	cursor, err := col.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding rating aggregates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []uuid.UUID
+	for cursor.Next(ctx) {
+		var agg VenueRatingAggregate
+		if err := cursor.Decode(&agg); err != nil {
+			return nil, fmt.Errorf("error decoding rating aggregate: %v", err)
+		}
+		ids = append(ids, agg.VenueID)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+
+	return ids, nil
+}
+
+// ReplaceRatingAggregates makes the given set the stored aggregates. Each venue's aggregate is
+// replaced in place, so readers never see it missing, and only the aggregates of venues left
+// out of the set are deleted afterwards.
+func (mdb *MongodbRepo) ReplaceRatingAggregates(ctx context.Context, aggregates []*VenueRatingAggregate) error {
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	venueIds := make([]uuid.UUID, 0, len(aggregates))
+	if len(aggregates) > 0 {
+		writes := make([]mongo.WriteModel, 0, len(aggregates))
+		now := time.Now()
+		for _, agg := range aggregates {
+			agg.ID = primitive.NilObjectID
+			agg.UpdatedAt = now
+			venueIds = append(venueIds, agg.VenueID)
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"venue_id": agg.VenueID}).
+				SetReplacement(agg).
+				SetUpsert(true))
+		}
+		if _, err := col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("error replacing rating aggregates: %v", err)
+		}
+	}
+
+	if _, err := col.DeleteMany(ctx, bson.M{"venue_id": bson.M{"$nin": venueIds}}); err != nil {
+		return fmt.Errorf("error deleting stale rating aggregates: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) DeleteRatingAggregate(ctx context.Context, venueId uuid.UUID) error {
+	col, err := mdb.GetCollection(ctx, RatingAggregatesDbName, RatingAggregatesColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.DeleteOne(ctx, bson.M{"venue_id": venueId}); err != nil {
+		return fmt.Errorf("error deleting rating aggregate: %v", err)
+	}
+
+	return nil
+}
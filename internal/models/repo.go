@@ -1,6 +1,9 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/supabase-community/supabase-go"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -8,10 +11,15 @@ import (
 
 var Validate = validator.New()
 
+// ErrServiceRoleUnavailable is returned by admin and background writes when no service role key is configured
+var ErrServiceRoleUnavailable = errors.New("supabase service role key is not configured")
+
 type SupabaseRepo struct {
 	supabaseClient *supabase.Client
-	url            string
-	key            string
+	// serviceClient bypasses row level security; only for admin actions and background jobs
+	serviceClient *supabase.Client
+	url           string
+	key           string
 }
 
 func SupabaseNewRepo(supabaseClient *supabase.Client, url, key string) *SupabaseRepo {
@@ -39,6 +47,30 @@ func (su *SupabaseRepo) GetAuthenticatedClient(accessToken string) (*supabase.Cl
 	return supabase.NewClient(su.url, su.key, options)
 }
 
+// SetServiceRoleKey enables the service role client used by writes that act on behalf of
+// the platform rather than a signed-in user
+func (su *SupabaseRepo) SetServiceRoleKey(serviceKey string) error {
+	if serviceKey == "" {
+		su.serviceClient = nil
+		return nil
+	}
+
+	client, err := supabase.NewClient(su.url, serviceKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create service role client: %v", err)
+	}
+	su.serviceClient = client
+	return nil
+}
+
+// getServiceClient returns the service role client, or ErrServiceRoleUnavailable
+func (su *SupabaseRepo) getServiceClient() (*supabase.Client, error) {
+	if su.serviceClient == nil {
+		return nil, ErrServiceRoleUnavailable
+	}
+	return su.serviceClient, nil
+}
+
 type MongodbRepo struct {
 	mongodbClient *mongo.Client
 }
@@ -8,6 +8,13 @@ type ApiResponse struct {
 	Page    int         `json:"page,omitempty"`
 	Limit   int         `json:"limit,omitempty"`
 	Total   int         `json:"total,omitempty"`
+	// Cursors of the neighbouring pages, for lists paginated by cursor
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	// Truncated is set when the list leaves out matches it could not rank
+	Truncated bool `json:"truncated,omitempty"`
+	// Facets counts the results of each filter option, for searches that ask for them
+	Facets interface{} `json:"facets,omitempty"`
 }
 
 func SuccessResponse(data interface{}, message string) ApiResponse {
@@ -34,3 +41,16 @@ func PaginatedResponse(data interface{}, page, limit, total int) ApiResponse {
 		Total:   total,
 	}
 }
+
+// WithCursors adds the cursors of the neighbouring pages to a paginated response
+func (r ApiResponse) WithCursors(next, prev string) ApiResponse {
+	r.NextCursor = next
+	r.PrevCursor = prev
+	return r
+}
+
+// WithFacets adds filter counts to a search response
+func (r ApiResponse) WithFacets(facets interface{}) ApiResponse {
+	r.Facets = facets
+	return r
+}
@@ -20,6 +20,8 @@ type VenueReview struct {
 	Title   string   `bson:"title" json:"title"`
 	Comment string   `bson:"comment" json:"comment"`
 	Images  []string `bson:"images" json:"images"`
+	// Storage public IDs for Images, in the same order; used to clean up uploaded assets
+	ImagePublicIDs []string `bson:"image_public_ids,omitempty" json:"-"`
 
 	// Event-Specific Context (Crucial for Event Venues)
 	EventID    uuid.UUID `bson:"event_id" json:"event_id,omitempty"` // NEW: Optional link if reviews are tied to a user-created 'Event' document
@@ -33,8 +35,49 @@ type VenueReview struct {
 	// Aggregated/Structured Feedback (For Filtering and Quick Stats)
 	LikedFeatures []string `bson:"liked_features" json:"liked_features"` // NEW: Structured feedback (e.g., ["Location", "Cleanliness", "AV Equipment"])
 
+	// Verification (derived from BookingID at creation time)
+	VerifiedBooking bool `bson:"verified_booking" json:"verified_booking"` // true when the review is backed by a completed booking of the reviewer
+
+	// Double-blind publishing: hidden from the public and the host until this time,
+	// or until the host submits their review of the guest for the same booking
+	BlindUntil *time.Time `bson:"blind_until,omitempty" json:"blind_until,omitempty"`
+	// Revealed is set once the blind period has ended, early or at BlindUntil. The rating only
+	// counts towards the public venue aggregate from then on, so it cannot give the review away.
+	Revealed bool `bson:"revealed,omitempty" json:"-"`
+
+	// Public reply from the venue's host (at most one per review)
+	HostResponse *ReviewHostResponse `bson:"host_response,omitempty" json:"host_response,omitempty"`
+
+	// Moderation
+	ModerationReason string     `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"` // Set by an admin on rejection, or when auto-hidden
+	ModeratedBy      *uuid.UUID `bson:"moderated_by,omitempty" json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `bson:"moderated_at,omitempty" json:"moderated_at,omitempty"`
+	ReportCount      int        `bson:"report_count" json:"report_count"`
+
 	// Status & Timestamps
 	Status    string    `bson:"status" json:"status"` // NEW: e.g., "Pending Approval," "Approved," "Flagged"
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 }
+
+// ReviewReport is a single user's "report this review" flag; one per user per review
+type ReviewReport struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ReviewID  primitive.ObjectID `bson:"review_id" json:"review_id"`
+	UserID    uuid.UUID          `bson:"user_id" json:"user_id"`
+	Reason    string             `bson:"reason" json:"reason"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ReviewHostResponse is the venue host's public reply to a review
+type ReviewHostResponse struct {
+	ResponderID uuid.UUID `bson:"responder_id" json:"responder_id"` // The host, or an admin responding on their behalf
+	Body        string    `bson:"body" json:"body"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// IsRevealed reports whether the review is past its double-blind period at time now
+func (r *VenueReview) IsRevealed(now time.Time) bool {
+	return r.BlindUntil == nil || r.Revealed || !now.Before(*r.BlindUntil)
+}
@@ -2,28 +2,64 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joshua-takyi/ww/internal/helpers"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
 	ReviewStatusPending  = "Pending Approval"
 	ReviewStatusApproved = "Approved"
 	ReviewStatusFlagged  = "Flagged"
+	ReviewStatusRejected = "Rejected"
 	ReviewDbName         = "bashbay"
 	ReviewColName        = "venue_reviews"
+	ReviewReportsColName = "review_reports"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrReviewAlreadyExists = errors.New("a review already exists for this booking")
+	ErrAlreadyReported     = errors.New("you have already reported this review")
+	ErrTooManyReviewPhotos = errors.New("too many photos for this review")
 )
 
 type ReviewsRepo interface {
 	CreateReview(ctx context.Context, userId uuid.UUID, venueId uuid.UUID, review *VenueReview) (*VenueReview, error)
-	GetReviewsByVenue(ctx context.Context, venueId uuid.UUID) ([]*VenueReview, error)
+	GetReviewByID(ctx context.Context, reviewId primitive.ObjectID) (*VenueReview, error)
+	GetReviewByBooking(ctx context.Context, bookingId uuid.UUID) (*VenueReview, error)
+	GetReviewsByVenue(ctx context.Context, venueId uuid.UUID, status string, revealedOnly bool) ([]*VenueReview, error)
 	GetReviewsByUser(ctx context.Context, userId uuid.UUID) ([]*VenueReview, error)
-	UpdateReview(ctx context.Context, userId uuid.UUID, reviewId uuid.UUID, updatedReview *VenueReview) (*VenueReview, error)
-	DeleteReview(ctx context.Context, userId uuid.UUID, reviewId uuid.UUID) error
+	GetReviewsByStatus(ctx context.Context, status string, offset, limit int) ([]*VenueReview, int, error)
+	UpdateReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, updatedReview *VenueReview) (before, after *VenueReview, err error)
+	SetReviewStatus(ctx context.Context, reviewId primitive.ObjectID, status, reason string, moderatorId *uuid.UUID) (before, after *VenueReview, err error)
+	ReportReview(ctx context.Context, report *ReviewReport) (*VenueReview, error)
+	SetHostResponse(ctx context.Context, reviewId primitive.ObjectID, response *ReviewHostResponse) (*VenueReview, error)
+	AddReviewImages(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, urls, publicIDs []string, maxImages int) (*VenueReview, error)
+	RemoveReviewImage(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, url, publicID string) (*VenueReview, error)
+	ClearReviewImages(ctx context.Context, reviewId primitive.ObjectID) error
+	RevealReview(ctx context.Context, bookingId uuid.UUID, at time.Time) (before, after *VenueReview, err error)
+	RevealExpiredReview(ctx context.Context, now time.Time) (before, after *VenueReview, err error)
+	DeleteReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID) (*VenueReview, error)
+	DeleteVenueReviews(ctx context.Context, venueId uuid.UUID) ([]string, error)
+	IterateReviews(ctx context.Context, fn func(review *VenueReview) error) error
+	EnsureReviewIndexes(ctx context.Context) error
+}
+
+// IsValidReviewStatus reports whether status is one of the known review statuses
+func IsValidReviewStatus(status string) bool {
+	switch status {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusFlagged, ReviewStatusRejected:
+		return true
+	}
+	return false
 }
 
 func (r *VenueReview) BeforeCreate() error {
@@ -62,9 +98,11 @@ func (r *VenueReview) Sanitize() {
 	}
 	// Remove duplicate liked features
 	r.LikedFeatures = helpers.RemoveDuplicates(r.LikedFeatures)
-	// Remove profanity from comment
-	clean_comment := helpers.RemoveProfanity(r.Comment)
-	r.Comment = clean_comment
+}
+
+// Sanitize runs a host response through the same cleanup as review comments
+func (hr *ReviewHostResponse) Sanitize() {
+	hr.Body = helpers.StringTrim(hr.Body)
 }
 
 func (mdb *MongodbRepo) GetCollection(ctx context.Context, dbName, colName string) (*mongo.Collection, error) {
@@ -75,7 +113,81 @@ func (mdb *MongodbRepo) GetCollection(ctx context.Context, dbName, colName strin
 	return client, nil
 }
 
+// EnsureReviewIndexes creates the indexes the reviews collection relies on,
+// including the unique booking index that enforces one review per booking.
+func (mdb *MongodbRepo) EnsureReviewIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	indexes := []mongo.IndexModel{
+		// One review per booking
+		{
+			Keys: bson.D{{Key: "booking_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetName("booking_id_unique"),
+		},
+		// Venue listings, newest first
+		{
+			Keys: bson.D{
+				{Key: "venue_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("venue_status_created_at_idx"),
+		},
+		// Moderation queue
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("status_created_at_idx"),
+		},
+		// Reviews written by a user
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id_idx"),
+		},
+		// Blind reviews waiting to be revealed
+		{
+			Keys:    bson.D{{Key: "revealed", Value: 1}, {Key: "blind_until", Value: 1}},
+			Options: options.Index().SetName("revealed_blind_until_idx"),
+		},
+	}
+
+	_, err = col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	reportsCol, err := mdb.GetCollection(ctx, ReviewDbName, ReviewReportsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+	_, err = reportsCol.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// A user can only report a given review once
+		Keys: bson.D{
+			{Key: "review_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("review_user_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating report indexes: %v", err)
+	}
+
+	return nil
+}
+
 func (mdb *MongodbRepo) CreateReview(ctx context.Context, userId uuid.UUID, venueId uuid.UUID, review *VenueReview) (*VenueReview, error) {
+	review.UserID = userId
+	review.VenueID = venueId
+
 	if err := review.ValidateReview(); err != nil {
 		return nil, fmt.Errorf("invalid review data: %w", err)
 	}
@@ -90,21 +202,533 @@ func (mdb *MongodbRepo) CreateReview(ctx context.Context, userId uuid.UUID, venu
 	}
 	_, err = col.InsertOne(ctx, review)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrReviewAlreadyExists
+		}
 		return nil, fmt.Errorf("failed to insert review into database: %w", err)
 	}
 
 	return review, nil
 }
 
-func (mdb *MongodbRepo) GetReviewsByVenue(ctx context.Context, venueId uuid.UUID) ([]*VenueReview, error) {
-	return nil, nil
+func (mdb *MongodbRepo) GetReviewByID(ctx context.Context, reviewId primitive.ObjectID) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var review VenueReview
+	if err := col.FindOne(ctx, bson.M{"_id": reviewId}).Decode(&review); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error finding review: %v", err)
+	}
+
+	return &review, nil
+}
+
+// GetReviewByBooking returns the review attached to a booking, or nil if none exists yet
+func (mdb *MongodbRepo) GetReviewByBooking(ctx context.Context, bookingId uuid.UUID) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var review VenueReview
+	if err := col.FindOne(ctx, bson.M{"booking_id": bookingId}).Decode(&review); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding review by booking: %v", err)
+	}
+
+	return &review, nil
 }
+
+// GetReviewsByVenue lists a venue's reviews, optionally restricted to a single status
+// and to reviews whose double-blind period has ended
+func (mdb *MongodbRepo) GetReviewsByVenue(ctx context.Context, venueId uuid.UUID, status string, revealedOnly bool) ([]*VenueReview, error) {
+	filter := bson.M{"venue_id": venueId}
+	if status != "" {
+		filter["status"] = status
+	}
+	if revealedOnly {
+		filter = bson.M{"$and": bson.A{filter, revealedFilter(time.Now())}}
+	}
+	return mdb.findReviews(ctx, filter)
+}
+
 func (mdb *MongodbRepo) GetReviewsByUser(ctx context.Context, userId uuid.UUID) ([]*VenueReview, error) {
-	return nil, nil
+	return mdb.findReviews(ctx, bson.M{"user_id": userId})
+}
+
+func (mdb *MongodbRepo) findReviews(ctx context.Context, filter bson.M) ([]*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*VenueReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("error decoding reviews: %v", err)
+	}
+
+	return reviews, nil
+}
+
+// UpdateReview applies the editable content fields of updatedReview to a review owned by userId.
+// Relationships (user, venue, booking) and verification are never changed after creation,
+// and images are only managed through the photo upload methods. It returns the review as it
+// was just before and just after this update, so callers can act on exactly what changed.
+func (mdb *MongodbRepo) UpdateReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, updatedReview *VenueReview) (*VenueReview, *VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": reviewId, "user_id": userId}
+	set := bson.M{
+		"rating":         updatedReview.Rating,
+		"title":          updatedReview.Title,
+		"comment":        updatedReview.Comment,
+		"event_type":     updatedReview.EventType,
+		"guest_count":    updatedReview.GuestCount,
+		"liked_features": updatedReview.LikedFeatures,
+		"status":         updatedReview.Status,
+		"updated_at":     now,
+	}
+	unset := bson.M{
+		"moderated_by": "",
+		"moderated_at": "",
+	}
+	// Keep the reason when the edit itself was held by automatic moderation
+	if updatedReview.ModerationReason != "" {
+		set["moderation_reason"] = updatedReview.ModerationReason
+	} else {
+		unset["moderation_reason"] = ""
+	}
+	update := bson.M{"$set": set, "$unset": unset}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var before VenueReview
+	if err := col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, ErrReviewNotFound
+		}
+		return nil, nil, fmt.Errorf("error updating review: %v", err)
+	}
+
+	after := before
+	after.Rating = updatedReview.Rating
+	after.Title = updatedReview.Title
+	after.Comment = updatedReview.Comment
+	after.EventType = updatedReview.EventType
+	after.GuestCount = updatedReview.GuestCount
+	after.LikedFeatures = updatedReview.LikedFeatures
+	after.Status = updatedReview.Status
+	after.ModerationReason = updatedReview.ModerationReason
+	after.ModeratedBy = nil
+	after.ModeratedAt = nil
+	after.UpdatedAt = now
+
+	return &before, &after, nil
+}
+
+// GetReviewsByStatus returns the moderation queue for a status, oldest first
+func (mdb *MongodbRepo) GetReviewsByStatus(ctx context.Context, status string, offset, limit int) ([]*VenueReview, int, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{"status": status}
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting reviews: %v", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error finding reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*VenueReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, fmt.Errorf("error decoding reviews: %v", err)
+	}
+
+	return reviews, int(total), nil
+}
+
+// SetReviewStatus records a moderation decision. moderatorId is nil for automatic decisions.
+// It returns the review as it was just before and just after the decision.
+func (mdb *MongodbRepo) SetReviewStatus(ctx context.Context, reviewId primitive.ObjectID, status, reason string, moderatorId *uuid.UUID) (*VenueReview, *VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"status":            status,
+		"moderation_reason": reason,
+		"moderated_at":      now,
+		"updated_at":        now,
+	}
+	update := bson.M{"$set": set}
+	if moderatorId != nil {
+		set["moderated_by"] = moderatorId
+	} else {
+		update["$unset"] = bson.M{"moderated_by": ""}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var before VenueReview
+	if err := col.FindOneAndUpdate(ctx, bson.M{"_id": reviewId}, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, ErrReviewNotFound
+		}
+		return nil, nil, fmt.Errorf("error updating review status: %v", err)
+	}
+
+	after := before
+	after.Status = status
+	after.ModerationReason = reason
+	after.ModeratedBy = moderatorId
+	after.ModeratedAt = &now
+	after.UpdatedAt = now
+
+	return &before, &after, nil
+}
+
+// ReportReview stores a user's report and increments the review's report counter
+func (mdb *MongodbRepo) ReportReview(ctx context.Context, report *ReviewReport) (*VenueReview, error) {
+	reportsCol, err := mdb.GetCollection(ctx, ReviewDbName, ReviewReportsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if report.ID.IsZero() {
+		report.ID = primitive.NewObjectID()
+	}
+	if _, err := reportsCol.InsertOne(ctx, report); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, fmt.Errorf("error inserting review report: %v", err)
+	}
+
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result VenueReview
+	err = col.FindOneAndUpdate(ctx,
+		bson.M{"_id": report.ReviewID},
+		bson.M{"$inc": bson.M{"report_count": 1}},
+		opts,
+	).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error updating review report count: %v", err)
+	}
+
+	return &result, nil
+}
+
+// SetHostResponse stores the host's reply on a review; a nil response removes it
+func (mdb *MongodbRepo) SetHostResponse(ctx context.Context, reviewId primitive.ObjectID, response *ReviewHostResponse) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	update := bson.M{"$set": bson.M{"host_response": response}}
+	if response == nil {
+		update = bson.M{"$unset": bson.M{"host_response": ""}}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result VenueReview
+	if err := col.FindOneAndUpdate(ctx, bson.M{"_id": reviewId}, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error updating host response: %v", err)
+	}
+
+	return &result, nil
+}
+
+// AddReviewImages appends uploaded photos to a review owned by userId, as long as the
+// review would not exceed maxImages. Adding photos sends the review back to moderation.
+func (mdb *MongodbRepo) AddReviewImages(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, urls, publicIDs []string, maxImages int) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{
+		"_id":     reviewId,
+		"user_id": userId,
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$size": bson.M{"$ifNull": bson.A{"$images", bson.A{}}}},
+			maxImages - len(urls),
+		}},
+	}
+	update := bson.M{
+		"$push": bson.M{
+			"images":           bson.M{"$each": urls},
+			"image_public_ids": bson.M{"$each": publicIDs},
+		},
+		"$set": bson.M{
+			"status":     ReviewStatusPending,
+			"updated_at": time.Now(),
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result VenueReview
+	if err := col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			// The filter also holds the photo limit, so tell the two apart
+			owned, countErr := col.CountDocuments(ctx, bson.M{"_id": reviewId, "user_id": userId})
+			if countErr != nil {
+				return nil, fmt.Errorf("error adding review images: %v", countErr)
+			}
+			if owned == 0 {
+				return nil, ErrReviewNotFound
+			}
+			return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyReviewPhotos, maxImages)
+		}
+		return nil, fmt.Errorf("error adding review images: %v", err)
+	}
+
+	return &result, nil
 }
-func (mdb *MongodbRepo) UpdateReview(ctx context.Context, userId uuid.UUID, reviewId uuid.UUID, updatedReview *VenueReview) (*VenueReview, error) {
-	return nil, nil
+
+func (mdb *MongodbRepo) RemoveReviewImage(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, url, publicID string) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	pull := bson.M{"images": url}
+	// Legacy photos have no public ID to pull
+	if publicID != "" {
+		pull["image_public_ids"] = publicID
+	}
+	update := bson.M{
+		"$pull": pull,
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result VenueReview
+	if err := col.FindOneAndUpdate(ctx, bson.M{"_id": reviewId, "user_id": userId}, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error removing review image: %v", err)
+	}
+
+	return &result, nil
 }
-func (mdb *MongodbRepo) DeleteReview(ctx context.Context, userId uuid.UUID, reviewId uuid.UUID) error {
+
+// ClearReviewImages drops all photo references from a review (used after its assets are deleted)
+func (mdb *MongodbRepo) ClearReviewImages(ctx context.Context, reviewId primitive.ObjectID) error {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.UpdateOne(ctx, bson.M{"_id": reviewId}, bson.M{
+		"$set": bson.M{
+			"images":           []string{},
+			"image_public_ids": []string{},
+			"updated_at":       time.Now(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error clearing review images: %v", err)
+	}
+
 	return nil
 }
+
+// RevealReview ends the blind period of a booking's venue review early. It returns the review
+// just before and after, or nils when there was no blind review left to reveal.
+func (mdb *MongodbRepo) RevealReview(ctx context.Context, bookingId uuid.UUID, at time.Time) (*VenueReview, *VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{"booking_id": bookingId, "blind_until": bson.M{"$exists": true}, "revealed": bson.M{"$exists": false}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"revealed":    true,
+		"blind_until": bson.M{"$min": bson.A{"$blind_until", at}},
+	}}}
+	before, err := revealReview(ctx, col, filter, update)
+	if err != nil || before == nil {
+		return nil, nil, err
+	}
+
+	after := *before
+	after.Revealed = true
+	if before.BlindUntil.After(at) {
+		after.BlindUntil = &at
+	}
+	return before, &after, nil
+}
+
+// RevealExpiredReview marks one review whose blind period ended by now as revealed. It returns
+// the review just before and after, or nils when none is left.
+func (mdb *MongodbRepo) RevealExpiredReview(ctx context.Context, now time.Time) (*VenueReview, *VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{"blind_until": bson.M{"$lte": now}, "revealed": bson.M{"$exists": false}}
+	before, err := revealReview(ctx, col, filter, bson.M{"$set": bson.M{"revealed": true}})
+	if err != nil || before == nil {
+		return nil, nil, err
+	}
+
+	after := *before
+	after.Revealed = true
+	return before, &after, nil
+}
+
+// revealReview applies a reveal update to one matching review and returns it as it was before,
+// or nil when nothing matched. Matching on the missing flag makes each reveal happen once.
+func revealReview(ctx context.Context, col *mongo.Collection, filter bson.M, update interface{}) (*VenueReview, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var before VenueReview
+	if err := col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error revealing review: %v", err)
+	}
+	return &before, nil
+}
+
+// DeleteReview deletes a review owned by userId and returns it as it was when deleted
+func (mdb *MongodbRepo) DeleteReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID) (*VenueReview, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var deleted VenueReview
+	if err := col.FindOneAndDelete(ctx, bson.M{"_id": reviewId, "user_id": userId}).Decode(&deleted); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error deleting review: %v", err)
+	}
+
+	// Reports are meaningless once the review is gone
+	if reportsCol, err := mdb.GetCollection(ctx, ReviewDbName, ReviewReportsColName); err == nil {
+		if _, err := reportsCol.DeleteMany(ctx, bson.M{"review_id": reviewId}); err != nil {
+			fmt.Printf("Failed to delete reports for review %s: %v\n", reviewId.Hex(), err)
+		}
+	}
+
+	return &deleted, nil
+}
+
+// IterateReviews streams every stored review to fn, stopping at the first error
+func (mdb *MongodbRepo) IterateReviews(ctx context.Context, fn func(review *VenueReview) error) error {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	cursor, err := col.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("error finding reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var review VenueReview
+		if err := cursor.Decode(&review); err != nil {
+			return fmt.Errorf("error decoding review: %v", err)
+		}
+		if err := fn(&review); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// DeleteVenueReviews removes every review of a venue together with their reports and
+// returns the storage public IDs of the deleted review photos
+func (mdb *MongodbRepo) DeleteVenueReviews(ctx context.Context, venueId uuid.UUID) ([]string, error) {
+	col, err := mdb.GetCollection(ctx, ReviewDbName, ReviewColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	filter := bson.M{"venue_id": venueId}
+	cursor, err := col.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1, "image_public_ids": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding venue reviews: %v", err)
+	}
+	var reviews []VenueReview
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("error decoding venue reviews: %v", err)
+	}
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(reviews))
+	var publicIDs []string
+	for i, review := range reviews {
+		ids[i] = review.ID
+		publicIDs = append(publicIDs, review.ImagePublicIDs...)
+	}
+
+	reportsCol, err := mdb.GetCollection(ctx, ReviewDbName, ReviewReportsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+	if _, err := reportsCol.DeleteMany(ctx, bson.M{"review_id": bson.M{"$in": ids}}); err != nil {
+		return nil, fmt.Errorf("error deleting review reports: %v", err)
+	}
+	if _, err := col.DeleteMany(ctx, filter); err != nil {
+		return nil, fmt.Errorf("error deleting venue reviews: %v", err)
+	}
+
+	return publicIDs, nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SavedSearchesDbName  = "bashbay"
+	SavedSearchesColName = "saved_searches"
+)
+
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// SavedSearch is a venue search a user keeps to run again and be alerted about
+type SavedSearch struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID uuid.UUID          `bson:"user_id" json:"user_id"`
+	Name   string             `bson:"name" json:"name"`
+	// Params is the search as /venues/search query parameters, for clients to run it again
+	Params string `bson:"params" json:"params"`
+	// Query and Area are the parsed search, which alerts are matched against
+	Query VenueSearchQuery `bson:"query" json:"-"`
+	Area  *geo.Area        `bson:"area,omitempty" json:"-"`
+	// AlertedUntil is when venue activations were last checked against the search
+	AlertedUntil time.Time `bson:"alerted_until,omitempty" json:"-"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type SavedSearchesRepo interface {
+	CreateSavedSearch(ctx context.Context, search *SavedSearch) (*SavedSearch, error)
+	ListSavedSearches(ctx context.Context, userId uuid.UUID) ([]*SavedSearch, error)
+	CountSavedSearches(ctx context.Context, userId uuid.UUID) (int, error)
+	RenameSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID, name string) (*SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error
+	ListSavedSearchBatch(ctx context.Context, after primitive.ObjectID, limit int) ([]*SavedSearch, error)
+	MarkSavedSearchAlerted(ctx context.Context, id primitive.ObjectID, until time.Time) error
+	EnsureSavedSearchIndexes(ctx context.Context) error
+}
+
+func (mdb *MongodbRepo) EnsureSavedSearchIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("user_created_idx"),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) CreateSavedSearch(ctx context.Context, search *SavedSearch) (*SavedSearch, error) {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if search.ID.IsZero() {
+		search.ID = primitive.NewObjectID()
+	}
+	if _, err := col.InsertOne(ctx, search); err != nil {
+		return nil, fmt.Errorf("error creating saved search: %v", err)
+	}
+
+	return search, nil
+}
+
+// ListSavedSearches returns a user's saved searches, newest first
+func (mdb *MongodbRepo) ListSavedSearches(ctx context.Context, userId uuid.UUID) ([]*SavedSearch, error) {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := col.Find(ctx, bson.M{"user_id": userId}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding saved searches: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	searches := []*SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, fmt.Errorf("error decoding saved searches: %v", err)
+	}
+
+	return searches, nil
+}
+
+func (mdb *MongodbRepo) CountSavedSearches(ctx context.Context, userId uuid.UUID) (int, error) {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return 0, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	count, err := col.CountDocuments(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		return 0, fmt.Errorf("error counting saved searches: %v", err)
+	}
+
+	return int(count), nil
+}
+
+// RenameSavedSearch renames one of a user's saved searches; other users' searches are not found
+func (mdb *MongodbRepo) RenameSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID, name string) (*SavedSearch, error) {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	update := bson.M{"$set": bson.M{"name": name, "updated_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var search SavedSearch
+	err = col.FindOneAndUpdate(ctx, bson.M{"_id": id, "user_id": userId}, update, opts).Decode(&search)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error renaming saved search: %v", err)
+	}
+
+	return &search, nil
+}
+
+// DeleteSavedSearch deletes one of a user's saved searches; other users' searches are not found
+func (mdb *MongodbRepo) DeleteSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	res, err := col.DeleteOne(ctx, bson.M{"_id": id, "user_id": userId})
+	if err != nil {
+		return fmt.Errorf("error deleting saved search: %v", err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// ListSavedSearchBatch returns up to limit saved searches of any user with an ID after the given
+// one, in ID order, so background jobs can walk all of them
+func (mdb *MongodbRepo) ListSavedSearchBatch(ctx context.Context, after primitive.ObjectID, limit int) ([]*SavedSearch, error) {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, bson.M{"_id": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding saved searches: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var searches []*SavedSearch
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, fmt.Errorf("error decoding saved searches: %v", err)
+	}
+
+	return searches, nil
+}
+
+func (mdb *MongodbRepo) MarkSavedSearchAlerted(ctx context.Context, id primitive.ObjectID, until time.Time) error {
+	col, err := mdb.GetCollection(ctx, SavedSearchesDbName, SavedSearchesColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"alerted_until": until}}); err != nil {
+		return fmt.Errorf("error updating saved search: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package models
+
+// Kinds of search suggestion
+const (
+	SuggestVenue     = "venue"
+	SuggestRegion    = "region"
+	SuggestLocation  = "location"
+	SuggestVenueType = "venue_type"
+	SuggestTag       = "tag"
+)
+
+// SearchSuggestion completes what a user has typed into the search box
+type SearchSuggestion struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	// Slug links venue suggestions to their page
+	Slug string `json:"slug,omitempty"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SlugRedirectsDbName  = "bashbay"
+	SlugRedirectsColName = "venue_slug_redirects"
+)
+
+// SlugRedirect points a venue's previous slug at the venue so old links keep working
+type SlugRedirect struct {
+	Slug      string    `bson:"slug" json:"slug"`
+	VenueID   uuid.UUID `bson:"venue_id" json:"venue_id"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+type SlugRedirectsRepo interface {
+	SaveSlugRedirect(ctx context.Context, slug string, venueId uuid.UUID) error
+	GetSlugRedirect(ctx context.Context, slug string) (*SlugRedirect, error)
+	DeleteSlugRedirect(ctx context.Context, slug string) error
+	DeleteVenueSlugRedirects(ctx context.Context, venueId uuid.UUID) error
+	EnsureSlugRedirectIndexes(ctx context.Context) error
+}
+
+func (mdb *MongodbRepo) EnsureSlugRedirectIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, SlugRedirectsDbName, SlugRedirectsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("slug_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "venue_id", Value: 1}},
+			Options: options.Index().SetName("venue_id_idx"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+// SaveSlugRedirect records slug as a former slug of venueId, replacing any older target
+func (mdb *MongodbRepo) SaveSlugRedirect(ctx context.Context, slug string, venueId uuid.UUID) error {
+	col, err := mdb.GetCollection(ctx, SlugRedirectsDbName, SlugRedirectsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.UpdateOne(ctx,
+		bson.M{"slug": slug},
+		bson.M{"$set": bson.M{"venue_id": venueId, "created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving slug redirect: %v", err)
+	}
+
+	return nil
+}
+
+// GetSlugRedirect returns the redirect for a former slug, or nil if there is none
+func (mdb *MongodbRepo) GetSlugRedirect(ctx context.Context, slug string) (*SlugRedirect, error) {
+	col, err := mdb.GetCollection(ctx, SlugRedirectsDbName, SlugRedirectsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	var redirect SlugRedirect
+	if err := col.FindOne(ctx, bson.M{"slug": slug}).Decode(&redirect); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding slug redirect: %v", err)
+	}
+
+	return &redirect, nil
+}
+
+func (mdb *MongodbRepo) DeleteSlugRedirect(ctx context.Context, slug string) error {
+	col, err := mdb.GetCollection(ctx, SlugRedirectsDbName, SlugRedirectsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.DeleteOne(ctx, bson.M{"slug": slug}); err != nil {
+		return fmt.Errorf("error deleting slug redirect: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) DeleteVenueSlugRedirects(ctx context.Context, venueId uuid.UUID) error {
+	col, err := mdb.GetCollection(ctx, SlugRedirectsDbName, SlugRedirectsColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.DeleteMany(ctx, bson.M{"venue_id": venueId}); err != nil {
+		return fmt.Errorf("error deleting slug redirects: %v", err)
+	}
+
+	return nil
+}
@@ -19,8 +19,10 @@ type User struct {
 	Preferences map[string]string `db:"preferences" json:"preferences"`
 	PhoneNumber string            `db:"phone_number" json:"phone_number"`
 	AvatarURL   string            `db:"avatar_url" json:"avatar_url"`
-	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time         `db:"updated_at" json:"updated_at"`
+	// Resized renditions of the avatar, keyed by size name
+	AvatarVariants ImageVariants `db:"avatar_variants" json:"avatar_variants,omitempty"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
 }
 
 // OAuthTokenResponse represents the response from OAuth token exchange
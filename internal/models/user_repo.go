@@ -24,7 +24,7 @@ type UserRepo interface {
 	GetUser(ctx context.Context, id uuid.UUID, accessToken string) (*User, error)
 	UpdateUser(ctx context.Context, user map[string]interface{}, userid uuid.UUID, accessToken string) (*User, error)
 	DeleteUser(ctx context.Context, id uuid.UUID, accessToken string) error
-	UploadAvatar(ctx context.Context, userId uuid.UUID, imageURL string, accessToken string) (string, error)
+	UploadAvatar(ctx context.Context, userId uuid.UUID, imageURL string, variants ImageVariants, accessToken string) (string, error)
 	GetGoogleAuthURL(ctx context.Context, redirectTo string) (string, error)
 	ExchangeGoogleCode(ctx context.Context, code string) (*OAuthTokenResponse, error)
 }
@@ -98,7 +98,7 @@ func (su *SupabaseRepo) GetUser(ctx context.Context, id uuid.UUID, accessToken s
 	}
 
 	raw, status, err := client.From(ProfileTable).
-		Select("id,email,username,fullname,role,location,bio,preferences,phone_number,is_verified,avatar_url,created_at,updated_at", "", false).
+		Select("id,email,username,fullname,role,location,bio,preferences,phone_number,is_verified,avatar_url,avatar_variants,created_at,updated_at", "", false).
 		Eq("id", stringedId).
 		// Single().
 		Execute()
@@ -228,7 +228,7 @@ func (su *SupabaseRepo) RefreshToken(ctx context.Context, refreshToken string) (
 	return resp, nil
 }
 
-func (su *SupabaseRepo) UploadAvatar(ctx context.Context, userId uuid.UUID, imageURL string, accessToken string) (string, error) {
+func (su *SupabaseRepo) UploadAvatar(ctx context.Context, userId uuid.UUID, imageURL string, variants ImageVariants, accessToken string) (string, error) {
 	client := su.supabaseClient
 	if accessToken != "" {
 		authClient, err := su.GetAuthenticatedClient(accessToken)
@@ -239,7 +239,8 @@ func (su *SupabaseRepo) UploadAvatar(ctx context.Context, userId uuid.UUID, imag
 	}
 
 	raw, count, err := client.From(ProfileTable).Update(map[string]interface{}{
-		"avatar_url": imageURL,
+		"avatar_url":      imageURL,
+		"avatar_variants": variants,
 	}, "", "exact").Eq("id", userId.String()).Execute()
 	if err != nil {
 		return "", fmt.Errorf("failed to upload avatar: %v", err)
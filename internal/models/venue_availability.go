@@ -0,0 +1,155 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04"
+)
+
+// AvailabilityWindow asks for venues that are free on Date from Start to End, read in each
+// venue's own timezone. Without times the venue must be free for the whole day. Windows
+// running past midnight are not supported.
+type AvailabilityWindow struct {
+	Date  string `json:"date"`
+	Start string `json:"start_time,omitempty"`
+	End   string `json:"end_time,omitempty"`
+}
+
+// Validate reports a malformed window, wrapped in ErrInvalidVenueSearch
+func (w AvailabilityWindow) Validate() error {
+	if w.Date == "" {
+		return fmt.Errorf("%w: date is required with start_time and end_time", ErrInvalidVenueSearch)
+	}
+	if _, err := time.Parse(dateLayout, w.Date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidVenueSearch)
+	}
+	if w.Start == "" && w.End == "" {
+		return nil
+	}
+	if w.Start == "" || w.End == "" {
+		return fmt.Errorf("%w: start_time and end_time must be given together", ErrInvalidVenueSearch)
+	}
+	start, err := time.Parse(clockLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("%w: start_time must be HH:MM", ErrInvalidVenueSearch)
+	}
+	end, err := time.Parse(clockLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("%w: end_time must be HH:MM", ErrInvalidVenueSearch)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidVenueSearch)
+	}
+	return nil
+}
+
+// Bounds returns the window as instants in loc; a day window runs from midnight to midnight.
+// The window must be valid.
+func (w AvailabilityWindow) Bounds(loc *time.Location) (time.Time, time.Time) {
+	day, _ := time.ParseInLocation(dateLayout, w.Date, loc)
+	if w.Start == "" {
+		return day, day.AddDate(0, 0, 1)
+	}
+	start, _ := clockMinutes(w.Start)
+	end, _ := clockMinutes(w.End)
+	return day.Add(time.Duration(start) * time.Minute), day.Add(time.Duration(end) * time.Minute)
+}
+
+// Location returns the venue's timezone, UTC when it is unset or unknown
+func (a Availability) Location() *time.Location {
+	if a.Timezone != "" {
+		if loc, err := time.LoadLocation(a.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// Allows reports whether the host's rules leave the window open: the date is not blocked and,
+// when weekly hours are set, the window falls within one of that weekday's opening periods.
+// Day windows only need the venue to open that weekday. Bookings are checked separately.
+func (a Availability) Allows(w AvailabilityWindow) bool {
+	day, err := time.Parse(dateLayout, w.Date)
+	if err != nil || a.IsDateUnavailable(day) {
+		return false
+	}
+	if len(a.WeeklyHours) == 0 {
+		return true
+	}
+
+	hours := a.hoursOn(day.Weekday())
+	if w.Start == "" {
+		return len(hours) > 0
+	}
+	start, _ := clockMinutes(w.Start)
+	end, _ := clockMinutes(w.End)
+	for _, period := range hours {
+		opens, ok1 := clockMinutes(period.Start)
+		closes, ok2 := clockMinutes(period.End)
+		// Periods closing at midnight may be written as 24:00 or 00:00
+		if ok2 && closes == 0 {
+			closes = 24 * 60
+		}
+		if ok1 && ok2 && opens <= start && end <= closes {
+			return true
+		}
+	}
+	return false
+}
+
+// hoursOn finds the opening periods of a weekday, keyed "Mon".."Sun" or by full name in any case
+func (a Availability) hoursOn(day time.Weekday) []TimeRange {
+	name := day.String()
+	for key, hours := range a.WeeklyHours {
+		if strings.EqualFold(key, name[:3]) || strings.EqualFold(key, name) {
+			return hours
+		}
+	}
+	return nil
+}
+
+// clockMinutes parses H:MM or HH:MM, up to 24:00, into minutes after midnight
+func clockMinutes(clock string) (int, bool) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(clock), ":")
+	if !ok || len(mm) != 2 {
+		return 0, false
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+// ListVenueAvailability returns up to limit venues matching query, in the order of its sort,
+// with only their ID and availability set. The matches are read a page at a time until an
+// empty page, so a server row cap smaller than the page cannot cut the list short.
+func (su *SupabaseRepo) ListVenueAvailability(ctx context.Context, query VenueSearchQuery, limit int) ([]*Venue, error) {
+	var venues []*Venue
+	for len(venues) < limit {
+		q := applyVenueSearch(su.supabaseClient.From(VenuesTable).Select("id,availability", "", false), query)
+		end := len(venues) + min(venueRowPage, limit-len(venues)) - 1
+		data, _, err := applyVenueSort(q, query.Sort).Range(len(venues), end, "").Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get venue availability: %v", err)
+		}
+
+		page, err := decodeVenues(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		venues = append(venues, page...)
+	}
+	return venues, nil
+}
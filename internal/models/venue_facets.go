@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+// FacetCount is how many results a filter value would yield
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// RangeFacetCount is how many results fall in a band. Min is inclusive, Max exclusive and
+// nil for the open-ended top band.
+type RangeFacetCount struct {
+	Key   string   `json:"key"`
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
+}
+
+// VenueFacets counts the results of each filter option given the rest of the current search.
+// Options that no result would match are left out of the value lists.
+type VenueFacets struct {
+	VenueTypes []FacetCount      `json:"venue_type"`
+	Regions    []FacetCount      `json:"region"`
+	Price      []RangeFacetCount `json:"price"`
+	Capacity   []RangeFacetCount `json:"capacity"`
+	Amenities  []FacetCount      `json:"amenities"`
+	// Partial is set when more venues matched than could be counted
+	Partial bool `json:"partial,omitempty"`
+}
+
+// venueFacetColumns are the columns facets are counted on, and the availability windows are
+// checked against
+const venueFacetColumns = "id,venue_type,region,price_per_hour,capacity,amenities,availability"
+
+// ListVenuesForFacets returns up to limit venues matching query, by ID, with only their facet
+// columns set. The matches are read a page at a time until an empty page, so a server row cap
+// smaller than the page cannot cut the list short.
+func (su *SupabaseRepo) ListVenuesForFacets(ctx context.Context, query VenueSearchQuery, limit int) ([]*Venue, error) {
+	var venues []*Venue
+	var after []string
+	for len(venues) < limit {
+		q := applyVenueSearch(su.supabaseClient.From(VenuesTable).Select(venueFacetColumns, "", false), query, after...)
+		data, _, err := q.Order("id", &postgrest.OrderOpts{Ascending: true}).Limit(min(venueRowPage, limit-len(venues)), "").Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get venue facets: %v", err)
+		}
+
+		page, err := decodeVenues(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		venues = append(venues, page...)
+		after = []string{"id.gt." + page[len(page)-1].Id.String()}
+	}
+	return venues, nil
+}
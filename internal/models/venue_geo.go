@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/supabase-community/postgrest-go"
+)
+
+// venuesInAreaFunction is the PostGIS function behind LocateVenues, created by
+// supabase/migrations/20261018000000_venues_in_area.sql:
+//
+//	venues_in_area(center_lat float8, center_lng float8, radius_m float8,
+//	               min_lat float8, min_lng float8, max_lat float8, max_lng float8,
+//	               max_results int, skip_results int)
+//	returns table (id uuid, distance_m float8)
+//
+// returning active, non-deleted venues within radius_m of the centre (ST_DWithin) and inside
+// the envelope, nearest first. Arguments that do not apply are null.
+const venuesInAreaFunction = "venues_in_area"
+
+// locateBatch is how many rows one call reads; it stays under the API's maximum row count
+// so results are never cut short
+const locateBatch = 1000
+
+// NearbyVenue is a venue found by a geographic search
+type NearbyVenue struct {
+	VenueID uuid.UUID
+	// DistanceKm is measured from the search point, and nil when the search had none
+	DistanceKm *float64
+}
+
+// VenueLocation is the position of a venue
+type VenueLocation struct {
+	VenueID uuid.UUID
+	Point   geo.Point
+}
+
+// VenueLocator finds active venues inside a search area, nearest first when it has a centre
+type VenueLocator interface {
+	LocateVenues(ctx context.Context, area geo.Area, limit int) ([]NearbyVenue, error)
+}
+
+// LocateVenues searches with PostGIS through the venues_in_area function. A limit of zero
+// or less returns every venue in the area.
+func (su *SupabaseRepo) LocateVenues(ctx context.Context, area geo.Area, limit int) ([]NearbyVenue, error) {
+	params := map[string]interface{}{
+		"center_lat": nil,
+		"center_lng": nil,
+		"radius_m":   nil,
+		"min_lat":    nil,
+		"min_lng":    nil,
+		"max_lat":    nil,
+		"max_lng":    nil,
+	}
+	if area.Center != nil {
+		params["center_lat"] = area.Center.Lat
+		params["center_lng"] = area.Center.Lng
+		if area.RadiusKm > 0 {
+			params["radius_m"] = area.RadiusKm * 1000
+		}
+	}
+	if area.Bounds != nil {
+		params["min_lat"] = area.Bounds.MinLat
+		params["min_lng"] = area.Bounds.MinLng
+		params["max_lat"] = area.Bounds.MaxLat
+		params["max_lng"] = area.Bounds.MaxLng
+	}
+
+	var nearby []NearbyVenue
+	for {
+		batch := locateBatch
+		if limit > 0 && limit-len(nearby) < batch {
+			batch = limit - len(nearby)
+		}
+		params["max_results"] = batch
+		params["skip_results"] = len(nearby)
+
+		raw := su.supabaseClient.Rpc(venuesInAreaFunction, "", params)
+		var rows []struct {
+			ID        uuid.UUID `json:"id"`
+			DistanceM *float64  `json:"distance_m"`
+		}
+		if err := json.Unmarshal([]byte(raw), &rows); err != nil {
+			return nil, fmt.Errorf("failed to search venues by area: %s", raw)
+		}
+
+		for _, row := range rows {
+			found := NearbyVenue{VenueID: row.ID}
+			if row.DistanceM != nil && area.Center != nil {
+				km := *row.DistanceM / 1000
+				found.DistanceKm = &km
+			}
+			nearby = append(nearby, found)
+		}
+		if len(rows) < batch || (limit > 0 && len(nearby) >= limit) {
+			return nearby, nil
+		}
+	}
+}
+
+// ListVenueLocations returns the positions of all active venues that have coordinates
+func (su *SupabaseRepo) ListVenueLocations(ctx context.Context) ([]VenueLocation, error) {
+	type locationRow struct {
+		ID          uuid.UUID `json:"id"`
+		Coordinates *string   `json:"coordinates"`
+	}
+	var rows []locationRow
+	for {
+		data, _, err := su.supabaseClient.From(VenuesTable).Select("id,coordinates", "", false).
+			Eq("status", string(StatusActive)).
+			Is("deleted_at", "null").
+			Order("id", &postgrest.OrderOpts{Ascending: true}).
+			Range(len(rows), len(rows)+locateBatch-1, "").Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get venue locations: %v", err)
+		}
+
+		var batch []locationRow
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal venue locations: %v", err)
+		}
+		rows = append(rows, batch...)
+		if len(batch) < locateBatch {
+			break
+		}
+	}
+
+	locations := make([]VenueLocation, 0, len(rows))
+	for _, row := range rows {
+		if row.Coordinates == nil {
+			continue
+		}
+		var coords Coordinates
+		if err := coords.Scan(*row.Coordinates); err != nil {
+			return nil, fmt.Errorf("failed to parse coordinates of venue %s: %v", row.ID, err)
+		}
+		// Zero coordinates mean the host never placed the venue on the map
+		if coords.Latitude == 0 && coords.Longitude == 0 {
+			continue
+		}
+		locations = append(locations, VenueLocation{
+			VenueID: row.ID,
+			Point:   geo.Point{Lat: coords.Latitude, Lng: coords.Longitude},
+		})
+	}
+	return locations, nil
+}
@@ -0,0 +1,369 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+)
+
+var ErrInvalidVenueSearch = errors.New("invalid venue search")
+
+// Search terms are matched inside PostgREST filter strings, so they are limited to characters
+// that never need quoting there
+var reSearchTerm = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} &'_-]*$`)
+
+const (
+	// maxSearchTermLength bounds the terms of the column filters
+	maxSearchTermLength = 100
+	// maxSearchTextLength bounds full-text queries
+	maxSearchTextLength = 200
+)
+
+// VenueSort orders venue listings and searches. Every order ends with the venue ID so that
+// pages never overlap or skip venues.
+type VenueSort string
+
+const (
+	SortNewest       VenueSort = "newest"
+	SortPriceAsc     VenueSort = "price_asc"
+	SortPriceDesc    VenueSort = "price_desc"
+	SortCapacityAsc  VenueSort = "capacity_asc"
+	SortCapacityDesc VenueSort = "capacity_desc"
+	// SortRating puts the best rated venues first, then those with more reviews
+	SortRating VenueSort = "rating"
+	// SortPopularity puts the most viewed venues first, counting the views still retained
+	SortPopularity VenueSort = "popularity"
+	// SortDistance is only valid for searches around a point
+	SortDistance VenueSort = "distance"
+	// SortRelevance puts the best text matches first and is only valid for text searches
+	SortRelevance VenueSort = "relevance"
+)
+
+func (s VenueSort) Valid() bool {
+	switch s {
+	case SortNewest, SortPriceAsc, SortPriceDesc, SortCapacityAsc, SortCapacityDesc,
+		SortRating, SortPopularity, SortDistance, SortRelevance:
+		return true
+	}
+	return false
+}
+
+// VenueSearchQuery filters a venue search. Zero values leave a filter out.
+type VenueSearchQuery struct {
+	// IDs restricts the search to venues found by another index, e.g. a geographic search
+	IDs []uuid.UUID
+	// Text is a full-text query over name, vibe headline, description, tags and location.
+	// It is answered by the search index, never by the database.
+	Text string
+	// VenueTypes matches venues of any of the given types
+	VenueTypes []string
+	// Amenities matches venues satisfying all of the given amenity filters
+	Amenities   []AmenityFilter
+	MinPrice    *float64
+	MaxPrice    *float64
+	MinCapacity *int
+	MaxCapacity *int
+	// Location, Name and Description match case-insensitively anywhere in the field
+	Location    string
+	Name        string
+	Description string
+	Region      string
+	Status      VenueStatus
+	// Guests matches venues with room for that many people
+	Guests *int
+	// Window matches venues whose availability rules and bookings leave it free. Like Text, it
+	// is checked by the service rather than the database.
+	Window *AvailabilityWindow
+	// Sort orders the results; the database can only apply the column based orders, and
+	// orders it cannot apply fall back to newest first
+	Sort VenueSort
+}
+
+// IsEmpty reports whether the query has no filters
+func (q VenueSearchQuery) IsEmpty() bool {
+	return len(q.IDs) == 0 && q.Text == "" && len(q.VenueTypes) == 0 && len(q.Amenities) == 0 &&
+		q.MinPrice == nil && q.MaxPrice == nil && q.MinCapacity == nil && q.MaxCapacity == nil &&
+		q.Location == "" && q.Name == "" && q.Description == "" && q.Region == "" && q.Status == "" &&
+		q.Guests == nil && q.Window == nil
+}
+
+// Validate reports the first invalid filter, wrapped in ErrInvalidVenueSearch
+func (q VenueSearchQuery) Validate() error {
+	if utf8.RuneCountInString(q.Text) > maxSearchTextLength {
+		return fmt.Errorf("%w: q is too long", ErrInvalidVenueSearch)
+	}
+	for _, term := range q.VenueTypes {
+		if err := validateSearchTerm("venue_type", term); err != nil {
+			return err
+		}
+	}
+	for _, filter := range q.Amenities {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	for field, term := range map[string]string{
+		"location":    q.Location,
+		"name":        q.Name,
+		"description": q.Description,
+		"region":      q.Region,
+	} {
+		if term == "" {
+			continue
+		}
+		if err := validateSearchTerm(field, term); err != nil {
+			return err
+		}
+	}
+
+	if q.MinPrice != nil && *q.MinPrice < 0 {
+		return fmt.Errorf("%w: min_price cannot be negative", ErrInvalidVenueSearch)
+	}
+	if q.MaxPrice != nil && *q.MaxPrice < 0 {
+		return fmt.Errorf("%w: max_price cannot be negative", ErrInvalidVenueSearch)
+	}
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot exceed max_price", ErrInvalidVenueSearch)
+	}
+	if q.MinCapacity != nil && *q.MinCapacity < 0 {
+		return fmt.Errorf("%w: min_capacity cannot be negative", ErrInvalidVenueSearch)
+	}
+	if q.MaxCapacity != nil && *q.MaxCapacity < 0 {
+		return fmt.Errorf("%w: max_capacity cannot be negative", ErrInvalidVenueSearch)
+	}
+	if q.MinCapacity != nil && q.MaxCapacity != nil && *q.MinCapacity > *q.MaxCapacity {
+		return fmt.Errorf("%w: min_capacity cannot exceed max_capacity", ErrInvalidVenueSearch)
+	}
+
+	if q.Guests != nil && *q.Guests <= 0 {
+		return fmt.Errorf("%w: guests must be positive", ErrInvalidVenueSearch)
+	}
+	if q.Window != nil {
+		if err := q.Window.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if q.Sort != "" && !q.Sort.Valid() {
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidVenueSearch, q.Sort)
+	}
+
+	switch q.Status {
+	case "", StatusDraft, StatusPending, StatusActive, StatusInactive, StatusRejected:
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidVenueSearch, q.Status)
+	}
+
+	return nil
+}
+
+func validateSearchTerm(field, term string) error {
+	if len(term) > maxSearchTermLength {
+		return fmt.Errorf("%w: %s is too long", ErrInvalidVenueSearch, field)
+	}
+	if !reSearchTerm.MatchString(term) {
+		return fmt.Errorf("%w: %s contains unsupported characters", ErrInvalidVenueSearch, field)
+	}
+	return nil
+}
+
+// applyVenueSearch adds the filters of q to a venues query. The count and the data query of
+// a search both go through it so they always agree. Text and Window are left to the caller:
+// text narrows IDs to the venues the search index found, and windows are checked on the matches.
+// extra conditions, such as a cursor's key, are added to the same and=(...) group.
+func applyVenueSearch(b *postgrest.FilterBuilder, q VenueSearchQuery, extra ...string) *postgrest.FilterBuilder {
+	b = b.Is("deleted_at", "null")
+
+	if len(q.IDs) > 0 {
+		ids := make([]string, len(q.IDs))
+		for i, id := range q.IDs {
+			ids[i] = id.String()
+		}
+		b = b.In("id", ids)
+	}
+	if len(q.VenueTypes) > 0 {
+		// venue_type is an array column; PostgREST array operators are unreliable here, so the
+		// stringified array is matched instead, any one of the types being enough
+		conditions := make([]string, len(q.VenueTypes))
+		for i, venueType := range q.VenueTypes {
+			conditions[i] = "venue_type.ilike.*" + venueType + "*"
+		}
+		b = b.Or(strings.Join(conditions, ","), "")
+	}
+	// The client keeps one parameter per column, so conditions that may share a column go
+	// into a single and=(...) group
+	conditions := append([]string(nil), extra...)
+	for _, filter := range q.Amenities {
+		conditions = append(conditions, filter.condition())
+	}
+	if q.MinPrice != nil {
+		conditions = append(conditions, "price_per_hour.gte."+strconv.FormatFloat(*q.MinPrice, 'f', -1, 64))
+	}
+	if q.MaxPrice != nil {
+		conditions = append(conditions, "price_per_hour.lte."+strconv.FormatFloat(*q.MaxPrice, 'f', -1, 64))
+	}
+	if q.MinCapacity != nil {
+		conditions = append(conditions, "capacity.gte."+strconv.Itoa(*q.MinCapacity))
+	}
+	if q.MaxCapacity != nil {
+		conditions = append(conditions, "capacity.lte."+strconv.Itoa(*q.MaxCapacity))
+	}
+	if q.Guests != nil {
+		conditions = append(conditions, "capacity.gte."+strconv.Itoa(*q.Guests))
+	}
+	if len(conditions) > 0 {
+		b = b.And(strings.Join(conditions, ","), "")
+	}
+	if q.Location != "" {
+		b = b.Ilike("location", "%"+q.Location+"%")
+	}
+	if q.Name != "" {
+		b = b.Ilike("name", "%"+q.Name+"%")
+	}
+	if q.Description != "" {
+		b = b.Ilike("description", "%"+q.Description+"%")
+	}
+	if q.Region != "" {
+		b = b.Ilike("region", q.Region)
+	}
+	if q.Status != "" {
+		b = b.Eq("status", string(q.Status))
+	}
+
+	return b
+}
+
+// venueSortColumn is one column of the database order of a venue sort
+type venueSortColumn struct {
+	name      string
+	ascending bool
+}
+
+// venueSortColumns is the database order of s, newest first unless s sorts by another
+// column. The ID comes last so the order is total and can be paged by key.
+func venueSortColumns(s VenueSort) []venueSortColumn {
+	switch s {
+	case SortPriceAsc:
+		return []venueSortColumn{{"price_per_hour", true}, {"id", true}}
+	case SortPriceDesc:
+		return []venueSortColumn{{"price_per_hour", false}, {"id", true}}
+	case SortCapacityAsc:
+		return []venueSortColumn{{"capacity", true}, {"id", true}}
+	case SortCapacityDesc:
+		return []venueSortColumn{{"capacity", false}, {"id", true}}
+	case SortRating:
+		// The rating columns mirror the rating aggregates, so the database can rank by them
+		return []venueSortColumn{{"rating_average", false}, {"rating_count", false}, {"created_at", false}, {"id", true}}
+	default:
+		return []venueSortColumn{{"created_at", false}, {"id", true}}
+	}
+}
+
+// applyVenueSort orders a venues query by the column based part of s, newest first otherwise
+func applyVenueSort(b *postgrest.FilterBuilder, s VenueSort) *postgrest.FilterBuilder {
+	for _, column := range venueSortColumns(s) {
+		b = b.Order(column.name, &postgrest.OrderOpts{Ascending: column.ascending})
+	}
+	return b
+}
+
+// VenueCursorKey is the cursor key of a venue in a list in the database order of s
+func VenueCursorKey(v *Venue, s VenueSort) []string {
+	columns := venueSortColumns(s)
+	key := make([]string, len(columns))
+	for i, column := range columns {
+		switch column.name {
+		case "price_per_hour":
+			key[i] = strconv.FormatFloat(v.PricePerHour, 'f', -1, 64)
+		case "capacity":
+			key[i] = strconv.Itoa(v.Capacity)
+		case "rating_average":
+			key[i] = strconv.FormatFloat(v.RatingAverage, 'f', -1, 64)
+		case "rating_count":
+			key[i] = strconv.Itoa(v.RatingCount)
+		case "created_at":
+			key[i] = v.CreatedAt.UTC().Format(time.RFC3339Nano)
+		case "id":
+			key[i] = v.Id.String()
+		}
+	}
+	return key
+}
+
+// venueKeyCondition is the PostgREST condition selecting the venues past cursor in the order
+// of s. Key values are parsed and formatted again so nothing from the token reaches the
+// filter unchecked.
+func venueKeyCondition(cursor *Cursor, s VenueSort) (string, error) {
+	columns := venueSortColumns(s)
+	if len(cursor.Key) != len(columns) {
+		return "", ErrInvalidCursor
+	}
+
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		raw := cursor.Key[i]
+		switch column.name {
+		case "price_per_hour", "rating_average":
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return "", ErrInvalidCursor
+			}
+			values[i] = strconv.FormatFloat(f, 'f', -1, 64)
+		case "capacity", "rating_count":
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return "", ErrInvalidCursor
+			}
+			values[i] = strconv.Itoa(n)
+		case "created_at":
+			t, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return "", ErrInvalidCursor
+			}
+			values[i] = t.UTC().Format(time.RFC3339Nano)
+		case "id":
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return "", ErrInvalidCursor
+			}
+			values[i] = id.String()
+		}
+		values[i] = `"` + values[i] + `"`
+	}
+
+	// Venues past the key share its first i values and go past it on the next one
+	branches := make([]string, len(columns))
+	for i, column := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j].name+".eq."+values[j])
+		}
+		op := "gt"
+		if column.ascending == cursor.Backward {
+			op = "lt"
+		}
+		parts = append(parts, column.name+"."+op+"."+values[i])
+		if len(parts) == 1 {
+			branches[i] = parts[0]
+		} else {
+			branches[i] = "and(" + strings.Join(parts, ",") + ")"
+		}
+	}
+	return "or(" + strings.Join(branches, ",") + ")", nil
+}
+
+// countOption asks PostgREST for an exact count only when it is needed
+func countOption(withTotal bool) string {
+	if withTotal {
+		return "exact"
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	VenueStatusHistoryDbName  = "bashbay"
+	VenueStatusHistoryColName = "venue_status_history"
+)
+
+// VenueStatusChange records one lifecycle transition of a venue and who made it
+type VenueStatusChange struct {
+	VenueID   uuid.UUID   `bson:"venue_id" json:"venue_id"`
+	From      VenueStatus `bson:"from" json:"from"`
+	To        VenueStatus `bson:"to" json:"to"`
+	Reason    string      `bson:"reason,omitempty" json:"reason,omitempty"`
+	ActorID   uuid.UUID   `bson:"actor_id" json:"actor_id"`
+	CreatedAt time.Time   `bson:"created_at" json:"created_at"`
+}
+
+type VenueStatusHistoryRepo interface {
+	RecordVenueStatusChange(ctx context.Context, change *VenueStatusChange) error
+	GetVenueStatusHistory(ctx context.Context, venueId uuid.UUID, limit int) ([]*VenueStatusChange, error)
+	ListVenueStatusChangesSince(ctx context.Context, to VenueStatus, since time.Time, limit int) ([]*VenueStatusChange, error)
+	EnsureVenueStatusHistoryIndexes(ctx context.Context) error
+	DeleteVenueStatusHistory(ctx context.Context, venueId uuid.UUID) error
+}
+
+func (mdb *MongodbRepo) EnsureVenueStatusHistoryIndexes(ctx context.Context) error {
+	col, err := mdb.GetCollection(ctx, VenueStatusHistoryDbName, VenueStatusHistoryColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "venue_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("venue_created_idx"),
+		},
+		{
+			// Background jobs look up recent transitions into a status
+			Keys:    bson.D{{Key: "to", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("to_created_idx"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %v", err)
+	}
+
+	return nil
+}
+
+func (mdb *MongodbRepo) RecordVenueStatusChange(ctx context.Context, change *VenueStatusChange) error {
+	col, err := mdb.GetCollection(ctx, VenueStatusHistoryDbName, VenueStatusHistoryColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.InsertOne(ctx, change); err != nil {
+		return fmt.Errorf("error recording venue status change: %v", err)
+	}
+
+	return nil
+}
+
+// GetVenueStatusHistory returns up to limit status changes of a venue, newest first
+func (mdb *MongodbRepo) GetVenueStatusHistory(ctx context.Context, venueId uuid.UUID, limit int) ([]*VenueStatusChange, error) {
+	col, err := mdb.GetCollection(ctx, VenueStatusHistoryDbName, VenueStatusHistoryColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, bson.M{"venue_id": venueId}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding venue status history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	changes := []*VenueStatusChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("error decoding venue status history: %v", err)
+	}
+
+	return changes, nil
+}
+
+// ListVenueStatusChangesSince returns up to limit transitions into status to made after since,
+// oldest first
+func (mdb *MongodbRepo) ListVenueStatusChangesSince(ctx context.Context, to VenueStatus, since time.Time, limit int) ([]*VenueStatusChange, error) {
+	col, err := mdb.GetCollection(ctx, VenueStatusHistoryDbName, VenueStatusHistoryColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := col.Find(ctx, bson.M{"to": to, "created_at": bson.M{"$gt": since}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding venue status changes: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	changes := []*VenueStatusChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("error decoding venue status changes: %v", err)
+	}
+
+	return changes, nil
+}
+
+func (mdb *MongodbRepo) DeleteVenueStatusHistory(ctx context.Context, venueId uuid.UUID) error {
+	col, err := mdb.GetCollection(ctx, VenueStatusHistoryDbName, VenueStatusHistoryColName)
+	if err != nil {
+		return fmt.Errorf("error getting collection: %v", err)
+	}
+
+	if _, err := col.DeleteMany(ctx, bson.M{"venue_id": venueId}); err != nil {
+		return fmt.Errorf("error deleting venue status history: %v", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -49,10 +50,13 @@ type HostViewStats struct {
 type VenueViewsRepo interface {
 	TrackVenueView(ctx context.Context, view *VenueView) error
 	GetVenueViewStats(ctx context.Context, venueId string, days int) (*VenueViewStats, error)
-	GetVenueViewHistory(ctx context.Context, venueId string, limit int) ([]*VenueView, error)
+	CountVenueViews(ctx context.Context, venueIds []string) (map[string]int64, error)
+	GetVenueViewHistory(ctx context.Context, venueId string, cursor *Cursor, limit int) ([]*VenueView, error)
 	GetHostViewStats(ctx context.Context, hostId string, days int) (*HostViewStats, error)
-	GetHostViewHistory(ctx context.Context, hostId string, limit int) ([]*VenueView, error)
+	GetHostViewHistory(ctx context.Context, hostId string, cursor *Cursor, limit int) ([]*VenueView, error)
+	CountHostViews(ctx context.Context, hostId string) (int64, error)
 	EnsureIndexes(ctx context.Context) error
+	DeleteVenueViews(ctx context.Context, venueId string) error
 }
 
 // EnsureIndexes creates necessary indexes including TTL
@@ -161,6 +165,44 @@ func (mdb *MongodbRepo) TrackVenueView(ctx context.Context, view *VenueView) err
 }
 
 // GetVenueViewStats returns aggregated view statistics
+// CountVenueViews returns the number of retained views per venue; venues without views are left out
+func (mdb *MongodbRepo) CountVenueViews(ctx context.Context, venueIds []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(venueIds) == 0 {
+		return counts, nil
+	}
+	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting collection: %v", err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"venue_id": bson.M{"$in": venueIds}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$venue_id",
+			"views": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error counting venue views: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		VenueID string `bson:"_id"`
+		Views   int64  `bson:"views"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("error decoding venue views: %v", err)
+	}
+	for _, result := range results {
+		counts[result.VenueID] = result.Views
+	}
+
+	return counts, nil
+}
+
 func (mdb *MongodbRepo) GetVenueViewStats(ctx context.Context, venueId string, days int) (*VenueViewStats, error) {
 	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
 	if err != nil {
@@ -230,30 +272,51 @@ func (mdb *MongodbRepo) GetVenueViewStats(ctx context.Context, venueId string, d
 }
 
 // GetVenueViewHistory returns recent view records
-func (mdb *MongodbRepo) GetVenueViewHistory(ctx context.Context, venueId string, limit int) ([]*VenueView, error) {
+func (mdb *MongodbRepo) GetVenueViewHistory(ctx context.Context, venueId string, cursor *Cursor, limit int) ([]*VenueView, error) {
+	return mdb.viewHistory(ctx, bson.M{"venue_id": venueId}, cursor, limit)
+}
+
+// viewHistory returns up to limit views matching filter past cursor, newest first
+func (mdb *MongodbRepo) viewHistory(ctx context.Context, filter bson.M, cursor *Cursor, limit int) ([]*VenueView, error) {
 	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting collection: %v", err)
 	}
 
+	keyFilter, direction, err := timeKeyFilter(cursor, "viewed_at", "_id", func(id string) (interface{}, error) {
+		return primitive.ObjectIDFromHex(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	opts := options.Find().
-		SetSort(bson.D{{Key: "viewed_at", Value: -1}}).
+		SetSort(bson.D{{Key: "viewed_at", Value: direction}, {Key: "_id", Value: direction}}).
 		SetLimit(int64(limit))
 
-	cursor, err := col.Find(ctx, bson.M{"venue_id": venueId}, opts)
+	cursorRes, err := col.Find(ctx, bson.M{"$and": bson.A{filter, keyFilter}}, opts)
 	if err != nil {
 		return nil, fmt.Errorf("error finding venue views: %v", err)
 	}
-	defer cursor.Close(ctx)
+	defer cursorRes.Close(ctx)
 
 	var views []*VenueView
-	if err := cursor.All(ctx, &views); err != nil {
+	if err := cursorRes.All(ctx, &views); err != nil {
 		return nil, fmt.Errorf("error decoding venue views: %v", err)
 	}
+	// Backward pages are fetched oldest first
+	if direction > 0 {
+		slices.Reverse(views)
+	}
 
 	return views, nil
 }
 
+// ViewCursorKey is the cursor key of a view in a view history
+func ViewCursorKey(v *VenueView) []string {
+	return timeKey(v.ViewedAt, v.ID.Hex())
+}
+
 // GetHostViewStats returns aggregated view statistics for all venues owned by a host
 func (mdb *MongodbRepo) GetHostViewStats(ctx context.Context, hostId string, days int) (*HostViewStats, error) {
 	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
@@ -348,26 +411,33 @@ func (mdb *MongodbRepo) GetHostViewStats(ctx context.Context, hostId string, day
 }
 
 // GetHostViewHistory returns recent view records for all venues owned by a host
-func (mdb *MongodbRepo) GetHostViewHistory(ctx context.Context, hostId string, limit int) ([]*VenueView, error) {
+func (mdb *MongodbRepo) GetHostViewHistory(ctx context.Context, hostId string, cursor *Cursor, limit int) ([]*VenueView, error) {
+	return mdb.viewHistory(ctx, bson.M{"host_id": hostId}, cursor, limit)
+}
+
+func (mdb *MongodbRepo) CountHostViews(ctx context.Context, hostId string) (int64, error) {
 	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting collection: %v", err)
+		return 0, fmt.Errorf("error getting collection: %v", err)
 	}
 
-	opts := options.Find().
-		SetSort(bson.D{{Key: "viewed_at", Value: -1}}).
-		SetLimit(int64(limit))
+	count, err := col.CountDocuments(ctx, bson.M{"host_id": hostId})
+	if err != nil {
+		return 0, fmt.Errorf("error counting host views: %v", err)
+	}
 
-	cursor, err := col.Find(ctx, bson.M{"host_id": hostId}, opts)
+	return count, nil
+}
+
+func (mdb *MongodbRepo) DeleteVenueViews(ctx context.Context, venueId string) error {
+	col, err := mdb.GetCollection(ctx, VenueViewsDbName, VenueViewsColName)
 	if err != nil {
-		return nil, fmt.Errorf("error finding host views: %v", err)
+		return fmt.Errorf("error getting collection: %v", err)
 	}
-	defer cursor.Close(ctx)
 
-	var views []*VenueView
-	if err := cursor.All(ctx, &views); err != nil {
-		return nil, fmt.Errorf("error decoding host views: %v", err)
+	if _, err := col.DeleteMany(ctx, bson.M{"venue_id": venueId}); err != nil {
+		return fmt.Errorf("error deleting venue views: %v", err)
 	}
 
-	return views, nil
+	return nil
 }
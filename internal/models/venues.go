@@ -16,11 +16,30 @@ import (
 type VenueStatus string
 
 const (
+	StatusDraft    VenueStatus = "draft"
 	StatusPending  VenueStatus = "pending"
 	StatusActive   VenueStatus = "active"
 	StatusInactive VenueStatus = "inactive"
+	StatusRejected VenueStatus = "rejected"
 )
 
+// VenueCompleteness scores how far a draft listing is from being ready to submit
+type VenueCompleteness struct {
+	// Score is the weighted share of filled-in fields, 0 to 100
+	Score int `json:"score"`
+	// Ready is set once every required field is filled in
+	Ready bool `json:"ready"`
+	// Missing lists the required fields still to fill in
+	Missing []string `json:"missing"`
+	// Recommended lists optional fields that would improve the listing
+	Recommended []string `json:"recommended"`
+}
+
+// IsDeleted reports whether the venue has been soft deleted
+func (v *Venue) IsDeleted() bool {
+	return v.DeletedAt != nil
+}
+
 // Coordinates maps to PostGIS geography(Point,4326)
 type Coordinates struct {
 	Latitude  float64 `json:"lat"`
@@ -220,11 +239,15 @@ type Venue struct {
 	Name         string   `db:"name" json:"name,omitempty"`
 	VibeHeadline string   `db:"vibe_headline" json:"vibe_headline,omitempty"`
 	Description  string   `db:"description" json:"description,omitempty"`
-	Images       []string `db:"images" json:"images,omitempty"`
-	VenueType    []string `db:"venue_type" json:"venue_type,omitempty"`
-	Slug         string   `db:"slug" json:"slug,omitempty"`
-	Tags         []string `db:"tags" json:"tags,omitempty"`
-	Region       string   `db:"region" json:"region,omitempty" validate:"required"`
+	Images       []string `db:"images" json:"images,omitempty"` // the first image is the cover
+	// Cloudinary public IDs, index-aligned with Images, used to delete the stored files
+	ImagePublicIDs []string `db:"image_public_ids" json:"-"`
+	// Resized renditions, index-aligned with Images
+	ImageVariants []ImageVariants `db:"image_variants" json:"image_variants,omitempty"`
+	VenueType     []string        `db:"venue_type" json:"venue_type,omitempty"`
+	Slug          string          `db:"slug" json:"slug,omitempty"`
+	Tags          []string        `db:"tags" json:"tags,omitempty"`
+	Region        string          `db:"region" json:"region,omitempty" validate:"required"`
 	// CAPACITY & DIMENSIONS
 	Capacity          int `db:"capacity" json:"capacity,omitempty"`                       // Total max capacity
 	SeatingCapacity   int `db:"seating_capacity" json:"seating_capacity,omitempty"`       // NEW
@@ -238,6 +261,7 @@ type Venue struct {
 	LoadInAccess  string      `db:"load_in_access" json:"load_in_access,omitempty"`
 
 	// AMENITIES & RULES
+	// Amenities are keyed by AmenityCatalogue keys, with values of the amenity's type
 	Amenities               map[string]any `db:"amenities" json:"amenities,omitempty"`
 	Rules                   []string       `db:"rules" json:"rules,omitempty"`
 	AlcoholPolicy           string         `db:"alcohol_policy" json:"alcohol_policy,omitempty"`                       // NEW
@@ -256,10 +280,26 @@ type Venue struct {
 	SetupTakedownDuration float64  `db:"setup_takedown_duration" json:"setup_takedown_duration,omitempty"`
 	IncludedItems         []string `db:"included_items" json:"included_items,omitempty"`
 
+	// RATINGS (maintained from approved reviews; the full breakdown is only attached on detail views)
+	RatingAverage float64               `db:"rating_average" json:"rating_average"`
+	RatingCount   int                   `db:"rating_count" json:"rating_count"`
+	RatingSummary *VenueRatingAggregate `db:"-" json:"rating_summary,omitempty"`
+
+	// Only attached to drafts, to guide the host through the listing wizard
+	Completeness *VenueCompleteness `db:"-" json:"completeness,omitempty"`
+	// Only set by searches around a point
+	DistanceKm *float64 `db:"-" json:"distance_km,omitempty"`
+	// Only set by text searches: a snippet of each field that matched, keyed by field name
+	Highlights map[string]string `db:"-" json:"highlights,omitempty"`
+
 	// STATUS & ADMIN
 	CancellationPolicy string       `db:"cancellation_policy" json:"cancellation_policy,omitempty"`
 	Availability       Availability `db:"availability" json:"availability,omitempty"`
 	Status             VenueStatus  `db:"status" json:"status,omitempty"`
+	StatusReason       string       `db:"status_reason" json:"status_reason,omitempty"` // Why the venue was rejected, deactivated or held for review
+	DescriptionHeld    bool         `db:"-" json:"description_held,omitempty"`          // Only set in the admin review queue
+	StatusChangedAt    *time.Time   `db:"status_changed_at" json:"status_changed_at,omitempty"`
+	DeletedAt          *time.Time   `db:"deleted_at" json:"deleted_at,omitempty"` // Set while the venue sits in the host's trash
 	CreatedAt          time.Time    `db:"created_at" json:"created_at"`
 	UpdatedAt          time.Time    `db:"updated_at" json:"updated_at"`
 }
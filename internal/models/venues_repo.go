@@ -3,22 +3,45 @@ package models
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
 	"github.com/supabase-community/supabase-go"
 )
 
+var (
+	ErrVenueNotFound = errors.New("venue not found")
+	// ErrVenueBooked is returned when pending or confirmed bookings keep a venue from being deleted
+	ErrVenueBooked = errors.New("venue has upcoming bookings")
+)
+
 type VenuesRepo interface {
 	CreateVenue(ctx context.Context, venue *Venue, hostId uuid.UUID, accessToken string) (*Venue, error)
 	ListVenueByID(ctx context.Context, id uuid.UUID) (*Venue, error)
+	GetVenueWithAuth(ctx context.Context, id uuid.UUID, accessToken string) (*Venue, error)
+	AdminGetVenue(ctx context.Context, id uuid.UUID) (*Venue, error)
 	ListVenuesByHost(ctx context.Context, hostId uuid.UUID, offset, limit int, accessToken string) ([]*Venue, int, error)
-	ListVenues(ctx context.Context, offset, limit int) ([]*Venue, int, error)
+	ListVenuesByStatus(ctx context.Context, status VenueStatus, offset, limit int) ([]*Venue, int, error)
 	UpdateVenue(ctx context.Context, host_id uuid.UUID, venue_id uuid.UUID, venue map[string]interface{}, accessToken string) (*Venue, error)
-	DeleteVenue(ctx context.Context, host_id uuid.UUID, venue_id uuid.UUID, accessToken string) error
-	QueryVenues(ctx context.Context, query map[string]interface{}, offset, limit int) ([]*Venue, int, error)
+	AdminUpdateVenue(ctx context.Context, venue_id uuid.UUID, venue map[string]interface{}) (*Venue, error)
+	SoftDeleteVenue(ctx context.Context, venue_id uuid.UUID, at time.Time, accessToken string) error
+	AdminSoftDeleteVenue(ctx context.Context, venue_id uuid.UUID, at time.Time) error
+	PurgeVenue(ctx context.Context, venue_id uuid.UUID) error
+	QueryVenues(ctx context.Context, query VenueSearchQuery, offset, limit int, withTotal bool) ([]*Venue, int, error)
+	QueryVenuesByKey(ctx context.Context, query VenueSearchQuery, cursor *Cursor, limit int, withTotal bool) ([]*Venue, int, error)
+	SearchVenueIDs(ctx context.Context, query VenueSearchQuery, limit int, withTotal bool) ([]uuid.UUID, int, error)
+	ListVenuesForFacets(ctx context.Context, query VenueSearchQuery, limit int) ([]*Venue, error)
+	ListVenueAvailability(ctx context.Context, query VenueSearchQuery, limit int) ([]*Venue, error)
 	GetVenueBySlug(ctx context.Context, slug string) (*Venue, error)
 	CreateManyVenues(ctx context.Context, venues []*Venue, hostId uuid.UUID, accessToken string) ([]*Venue, error)
+	UpdateVenueRating(ctx context.Context, venueId uuid.UUID, average float64, count int) error
+	ListDeletedVenues(ctx context.Context, before time.Time, limit int) ([]*Venue, error)
+	ListDeletedVenuesByHost(ctx context.Context, hostId uuid.UUID, accessToken string) ([]*Venue, error)
+	ListVenueLocations(ctx context.Context) ([]VenueLocation, error)
 }
 
 func (su *SupabaseRepo) getClientWithAuth(accessToken string) *supabase.Client {
@@ -38,6 +61,18 @@ func convertRawToVenue(rawVenue map[string]interface{}) (*Venue, error) {
 		}
 		delete(rawVenue, "coordinates")
 	}
+	// Public IDs are hidden from the JSON representation, so they are copied over by hand
+	var publicIDs []string
+	if raw, exists := rawVenue["image_public_ids"]; exists {
+		if items, ok := raw.([]interface{}); ok {
+			for _, item := range items {
+				if id, ok := item.(string); ok {
+					publicIDs = append(publicIDs, id)
+				}
+			}
+		}
+		delete(rawVenue, "image_public_ids")
+	}
 
 	// Handle array fields that might come as strings from the database
 	arrayFields := []string{"images", "rules", "accessibility", "tags", "included_items", "venue_type", "amenities", "availability"} // Add other array fields as needed
@@ -81,6 +116,8 @@ func convertRawToVenue(rawVenue map[string]interface{}) (*Venue, error) {
 		return nil, fmt.Errorf("failed to unmarshal to venue struct: %v", err)
 	}
 
+	venue.ImagePublicIDs = publicIDs
+
 	// Parse coordinates back to struct
 	if coordStr != "" {
 		if err := venue.Coordinates.Scan([]byte(coordStr)); err != nil {
@@ -103,6 +140,8 @@ func venueToInsertMap(venue *Venue) (map[string]interface{}, error) {
 		"host_id":                    venue.HostId,
 		"name":                       venue.Name,
 		"images":                     venue.Images,
+		"image_public_ids":           venue.ImagePublicIDs,
+		"image_variants":             venue.ImageVariants,
 		"rules":                      venue.Rules,
 		"accessibility":              venue.Accessibility,
 		"venue_type":                 venue.VenueType,
@@ -134,11 +173,30 @@ func venueToInsertMap(venue *Venue) (map[string]interface{}, error) {
 		"load_in_access":             venue.LoadInAccess,
 		"availability":               venue.Availability,
 		"status":                     venue.Status,
+		"status_reason":              venue.StatusReason,
+		"status_changed_at":          venue.StatusChangedAt,
+		"deleted_at":                 venue.DeletedAt,
 		"created_at":                 venue.CreatedAt,
 		"updated_at":                 venue.UpdatedAt,
 	}, nil
 }
 
+// VenueColumns returns the database columns of a venue restricted to the given column names
+func VenueColumns(venue *Venue, columns []string) (map[string]interface{}, error) {
+	all, err := venueToInsertMap(venue)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(columns))
+	for _, col := range columns {
+		if value, ok := all[col]; ok {
+			out[col] = value
+		}
+	}
+	return out, nil
+}
+
 func (su *SupabaseRepo) CreateVenue(ctx context.Context, venue *Venue, hostId uuid.UUID, accessToken string) (*Venue, error) {
 	venueData, err := venueToInsertMap(venue)
 	if err != nil {
@@ -169,41 +227,56 @@ func (su *SupabaseRepo) GetVenueByID(ctx context.Context, id uuid.UUID) (*Venue,
 	return nil, nil
 }
 
-func (su *SupabaseRepo) ListVenues(ctx context.Context, offset, limit int) ([]*Venue, int, error) {
-	_, total, err := su.supabaseClient.From(VenuesTable).Select("*", "exact", false).Limit(0, "").Execute()
+// ListVenuesByStatus lists venues in one lifecycle status, oldest first so review queues are fair
+func (su *SupabaseRepo) ListVenuesByStatus(ctx context.Context, status VenueStatus, offset, limit int) ([]*Venue, int, error) {
+	_, total, err := su.supabaseClient.From(VenuesTable).Select("id", "exact", false).Is("deleted_at", "null").Eq("status", string(status)).Limit(1, "").Execute()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get venues count: %v", err)
 	}
 
-	data, _, err := su.supabaseClient.From(VenuesTable).Select("*", "exact", false).Range(offset, offset+limit-1, "").Execute()
+	data, _, err := su.supabaseClient.From(VenuesTable).Select("*", "exact", false).Is("deleted_at", "null").
+		Eq("status", string(status)).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		Range(offset, offset+limit-1, "").Execute()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get venues: %v", err)
 	}
 
-	var rawVenues []map[string]interface{}
-	if err := json.Unmarshal(data, &rawVenues); err != nil {
-		return nil, 0, fmt.Errorf("failed to unmarshal venues: %v", err)
-	}
-
-	venues := make([]*Venue, 0, len(rawVenues))
-	for _, raw := range rawVenues {
-		venue, err := convertRawToVenue(raw)
-		if err != nil {
-			return nil, 0, err
-		}
-		venues = append(venues, venue)
+	venues, err := decodeVenues(data)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return venues, int(total), nil
 }
 
 func (su *SupabaseRepo) ListVenueByID(ctx context.Context, id uuid.UUID) (*Venue, error) {
-	data, count, err := su.supabaseClient.From(VenuesTable).Select("*", "exact", false).Eq("id", id.String()).Execute()
+	return getVenue(su.supabaseClient, id)
+}
+
+// GetVenueWithAuth loads a venue as the user signed in with accessToken, so hosts also see
+// their venues that row level security hides from the anon key, such as those in the trash
+func (su *SupabaseRepo) GetVenueWithAuth(ctx context.Context, id uuid.UUID, accessToken string) (*Venue, error) {
+	return getVenue(su.getClientWithAuth(accessToken), id)
+}
+
+// AdminGetVenue loads any venue with the service role, including trashed ones
+func (su *SupabaseRepo) AdminGetVenue(ctx context.Context, id uuid.UUID) (*Venue, error) {
+	client, err := su.getServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	return getVenue(client, id)
+}
+
+func getVenue(client *supabase.Client, id uuid.UUID) (*Venue, error) {
+	data, count, err := client.From(VenuesTable).Select("*", "exact", false).Eq("id", id.String()).Execute()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get venue: %v", err)
 	}
 	if count == 0 {
-		return nil, fmt.Errorf("venue not found")
+		return nil, ErrVenueNotFound
 	}
 
 	var rawVenues []map[string]interface{}
@@ -211,7 +284,7 @@ func (su *SupabaseRepo) ListVenueByID(ctx context.Context, id uuid.UUID) (*Venue
 		return nil, fmt.Errorf("failed to unmarshal venue: %v", err)
 	}
 	if len(rawVenues) == 0 {
-		return nil, fmt.Errorf("venue not found")
+		return nil, ErrVenueNotFound
 	}
 
 	return convertRawToVenue(rawVenues[0])
@@ -220,39 +293,62 @@ func (su *SupabaseRepo) ListVenueByID(ctx context.Context, id uuid.UUID) (*Venue
 func (su *SupabaseRepo) ListVenuesByHost(ctx context.Context, hostId uuid.UUID, offset, limit int, accessToken string) ([]*Venue, int, error) {
 	client := su.getClientWithAuth(accessToken)
 
-	_, total, err := client.From(VenuesTable).Select("*", "exact", false).Eq("host_id", hostId.String()).Limit(0, "").Execute()
+	_, total, err := client.From(VenuesTable).Select("*", "exact", false).Is("deleted_at", "null").Eq("host_id", hostId.String()).Limit(0, "").Execute()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get venues count: %v", err)
 	}
 
-	data, _, err := client.From(VenuesTable).Select("*", "exact", false).Eq("host_id", hostId.String()).Range(offset, offset+limit-1, "").Execute()
+	data, _, err := client.From(VenuesTable).Select("*", "exact", false).Is("deleted_at", "null").Eq("host_id", hostId.String()).Range(offset, offset+limit-1, "").Execute()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get venues: %v", err)
 	}
 
-	var rawVenues []map[string]interface{}
-	if err := json.Unmarshal(data, &rawVenues); err != nil {
-		return nil, 0, fmt.Errorf("failed to unmarshal venues: %v", err)
-	}
-
-	venues := make([]*Venue, 0, len(rawVenues))
-	for _, raw := range rawVenues {
-		venue, err := convertRawToVenue(raw)
-		if err != nil {
-			return nil, 0, err
-		}
-		venues = append(venues, venue)
+	venues, err := decodeVenues(data)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return venues, int(total), nil
 }
 
 func (su *SupabaseRepo) UpdateVenue(ctx context.Context, host_id uuid.UUID, venue_id uuid.UUID, venue map[string]interface{}, accessToken string) (*Venue, error) {
-	if len(venue) == 0 {
-		return nil, fmt.Errorf("no fields to update")
+	updateData, err := venueUpdateData(venue)
+	if err != nil {
+		return nil, err
 	}
 
 	client := su.getClientWithAuth(accessToken)
+	data, count, err := client.From(VenuesTable).Update(updateData, "", "exact").Eq("id", venue_id.String()).Eq("host_id", host_id.String()).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update venue: %v", err)
+	}
+	return decodeUpdatedVenue(data, count)
+}
+
+// AdminUpdateVenue updates any venue with the service role, for changes made by the platform
+// rather than the venue's host
+func (su *SupabaseRepo) AdminUpdateVenue(ctx context.Context, venue_id uuid.UUID, venue map[string]interface{}) (*Venue, error) {
+	updateData, err := venueUpdateData(venue)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := su.getServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	data, count, err := client.From(VenuesTable).Update(updateData, "", "exact").Eq("id", venue_id.String()).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update venue: %v", err)
+	}
+	return decodeUpdatedVenue(data, count)
+}
+
+// venueUpdateData converts the values of a venue update to their column representation
+func venueUpdateData(venue map[string]interface{}) (map[string]interface{}, error) {
+	if len(venue) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
 
 	updateData := make(map[string]interface{})
 	for key, value := range venue {
@@ -276,11 +372,10 @@ func (su *SupabaseRepo) UpdateVenue(ctx context.Context, host_id uuid.UUID, venu
 			updateData[key] = value
 		}
 	}
+	return updateData, nil
+}
 
-	data, count, err := client.From(VenuesTable).Update(updateData, "", "exact").Eq("id", venue_id.String()).Eq("host_id", host_id.String()).Execute()
-	if err != nil {
-		return nil, fmt.Errorf("failed to update venue: %v", err)
-	}
+func decodeUpdatedVenue(data []byte, count int64) (*Venue, error) {
 	if count == 0 {
 		return nil, fmt.Errorf("no venue was updated")
 	}
@@ -296,211 +391,175 @@ func (su *SupabaseRepo) UpdateVenue(ctx context.Context, host_id uuid.UUID, venu
 	return convertRawToVenue(rawVenues[0])
 }
 
-func (su *SupabaseRepo) DeleteVenue(ctx context.Context, host_id uuid.UUID, venue_id uuid.UUID, accessToken string) error {
-	client := su.getClientWithAuth(accessToken)
+// Database functions from supabase/migrations that delete venues
+const (
+	softDeleteVenueFunction = "soft_delete_venue"
+	purgeVenueFunction      = "purge_venue"
+)
+
+// SoftDeleteVenue moves one of the host's venues to the trash at the given time, as the host
+// signed in with accessToken. It returns ErrVenueBooked, with nothing changed, when pending
+// or confirmed bookings end after that time.
+func (su *SupabaseRepo) SoftDeleteVenue(ctx context.Context, venue_id uuid.UUID, at time.Time, accessToken string) error {
+	return softDeleteVenue(su.getClientWithAuth(accessToken), venue_id, at)
+}
 
-	_, count, err := client.From(VenuesTable).Delete("", "exact").Eq("id", venue_id.String()).Execute()
+// AdminSoftDeleteVenue moves any venue to the trash with the service role, like SoftDeleteVenue
+func (su *SupabaseRepo) AdminSoftDeleteVenue(ctx context.Context, venue_id uuid.UUID, at time.Time) error {
+	client, err := su.getServiceClient()
 	if err != nil {
-		return fmt.Errorf("failed to delete venue: %v", err)
+		return err
 	}
-	if count == 0 {
-		return fmt.Errorf("no venue was deleted")
+	return softDeleteVenue(client, venue_id, at)
+}
+
+// softDeleteVenue checks for bookings and deletes the venue in one database call, so no
+// booking can slip in between
+func softDeleteVenue(client *supabase.Client, venue_id uuid.UUID, at time.Time) error {
+	raw := client.Rpc(softDeleteVenueFunction, "", map[string]interface{}{
+		"target_venue": venue_id,
+		"deleted_on":   at.UTC().Format(time.RFC3339Nano),
+	})
+
+	var rows []struct {
+		Deleted          bool `json:"deleted"`
+		UpcomingBookings int  `json:"upcoming_bookings"`
+	}
+	if err := json.Unmarshal([]byte(raw), &rows); err != nil {
+		return fmt.Errorf("failed to delete venue: %s", raw)
 	}
+	if len(rows) == 0 {
+		return ErrVenueNotFound
+	}
+	if !rows[0].Deleted {
+		return fmt.Errorf("%w: %d", ErrVenueBooked, rows[0].UpcomingBookings)
+	}
+	return nil
+}
 
+// PurgeVenue removes a trashed venue row for good with the service role, archiving its
+// bookings in the same transaction. Venues that are gone or not in the trash are left alone.
+func (su *SupabaseRepo) PurgeVenue(ctx context.Context, venue_id uuid.UUID) error {
+	client, err := su.getServiceClient()
+	if err != nil {
+		return err
+	}
+
+	raw := client.Rpc(purgeVenueFunction, "", map[string]interface{}{
+		"target_venue": venue_id,
+	})
+	var purged bool
+	if err := json.Unmarshal([]byte(raw), &purged); err != nil {
+		return fmt.Errorf("failed to purge venue: %s", raw)
+	}
 	return nil
 }
 
-func (su *SupabaseRepo) QueryVenues(ctx context.Context, query map[string]interface{}, offset, limit int) ([]*Venue, int, error) {
-	if len(query) == 0 {
-		return su.ListVenues(ctx, offset, limit)
-	}
-
-	// Build the base query for counting - select only id to avoid geometry parsing issues
-	// Using "*" with exact=true returns count in header, but PostgREST still tries to parse geometry
-	// So we select a simple column instead
-	countQuery := su.supabaseClient.From(VenuesTable).Select("id", "exact", false)
-
-	// Apply filters to count query
-	for key, value := range query {
-		switch key {
-		case "venue_type":
-			// venue_type is an array column
-			// Since PostgREST array operators are causing issues, use ILIKE as fallback
-			// This will match if the stringified array contains the search term
-			switch v := value.(type) {
-			case []string:
-				if len(v) > 0 {
-					// Match any of the venue types
-					for _, vt := range v {
-						countQuery = countQuery.Ilike("venue_type", "%"+vt+"%")
-					}
-				}
-			case []interface{}:
-				vals := make([]string, 0, len(v))
-				for _, item := range v {
-					if s, ok := item.(string); ok && s != "" {
-						vals = append(vals, s)
-					}
-				}
-				if len(vals) > 0 {
-					for _, vt := range vals {
-						countQuery = countQuery.Ilike("venue_type", "%"+vt+"%")
-					}
-				}
-			case string:
-				if v != "" {
-					// Single string value
-					countQuery = countQuery.Ilike("venue_type", "%"+v+"%")
-				}
-			}
-		case "min_price":
-			if minPrice, ok := value.(float64); ok {
-				countQuery = countQuery.Gte("price_per_hour", fmt.Sprintf("%f", minPrice))
-			}
-		case "max_price":
-			if maxPrice, ok := value.(float64); ok {
-				countQuery = countQuery.Lte("price_per_hour", fmt.Sprintf("%f", maxPrice))
-			}
-		case "min_capacity":
-			if minCap, ok := value.(int); ok {
-				countQuery = countQuery.Gte("capacity", fmt.Sprintf("%d", minCap))
-			}
-		case "max_capacity":
-			if maxCap, ok := value.(int); ok {
-				countQuery = countQuery.Lte("capacity", fmt.Sprintf("%d", maxCap))
-			}
-		case "location":
-			if location, ok := value.(string); ok && location != "" {
-				countQuery = countQuery.Ilike("location", "%"+location+"%")
-			}
-		case "status":
-			if status, ok := value.(string); ok && status != "" {
-				countQuery = countQuery.Ilike("status", status)
-			}
-		case "name":
-			if name, ok := value.(string); ok && name != "" {
-				countQuery = countQuery.Ilike("name", "%"+name+"%")
-			}
-		case "description":
-			if desc, ok := value.(string); ok && desc != "" {
-				countQuery = countQuery.Ilike("description", "%"+desc+"%")
-			}
-		case "amenities":
-			if amenities, ok := value.([]string); ok && len(amenities) > 0 {
-				for _, amenity := range amenities {
-					countQuery = countQuery.Ilike("amenities", "%"+amenity+"%")
-				}
-			}
-		case "region":
-			if region, ok := value.(string); ok && region != "" {
-				countQuery = countQuery.Ilike("region", region)
-			}
-		}
+// QueryVenues returns a page of the venues matching query in its sort order. The total number
+// of matches is only counted, in the same request, when withTotal is set.
+func (su *SupabaseRepo) QueryVenues(ctx context.Context, query VenueSearchQuery, offset, limit int, withTotal bool) ([]*Venue, int, error) {
+	mainQuery := applyVenueSearch(su.supabaseClient.From(VenuesTable).Select("*", countOption(withTotal), false), query)
+	mainQuery = applyVenueSort(mainQuery, query.Sort)
+
+	// Execute main query with pagination
+	data, total, err := mainQuery.Range(offset, offset+limit-1, "").Execute()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query venues: %v", err)
 	}
 
-	// Execute count query - use head request to avoid parsing issues
-	_, total, err := countQuery.Limit(1, "").Execute()
+	venues, err := decodeVenues(data)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get venues count: %v", err)
+		return nil, 0, err
 	}
+	return venues, int(total), nil
+}
 
-	// Build main query with same filters - select all columns
-	mainQuery := su.supabaseClient.From(VenuesTable).Select("*", "exact", false)
+// QueryVenuesByKey returns up to limit venues matching query that come after cursor in the
+// order of its sort, or the first ones without a cursor. Backward cursors return the venues
+// before the key, still in sort order. withTotal counts every venue matching query.
+func (su *SupabaseRepo) QueryVenuesByKey(ctx context.Context, query VenueSearchQuery, cursor *Cursor, limit int, withTotal bool) ([]*Venue, int, error) {
+	var conditions []string
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		condition, err := venueKeyCondition(cursor, query.Sort)
+		if err != nil {
+			return nil, 0, err
+		}
+		conditions = append(conditions, condition)
+	}
 
-	for key, value := range query {
-		switch key {
-		case "venue_type":
-			// venue_type is an array column - use ILIKE for compatibility
-			switch v := value.(type) {
-			case []string:
-				if len(v) > 0 {
-					for _, vt := range v {
-						mainQuery = mainQuery.Ilike("venue_type", "%"+vt+"%")
-					}
-				}
-			case []interface{}:
-				vals := make([]string, 0, len(v))
-				for _, item := range v {
-					if s, ok := item.(string); ok && s != "" {
-						vals = append(vals, s)
-					}
-				}
-				if len(vals) > 0 {
-					for _, vt := range vals {
-						mainQuery = mainQuery.Ilike("venue_type", "%"+vt+"%")
-					}
-				}
-			case string:
-				if v != "" {
-					mainQuery = mainQuery.Ilike("venue_type", "%"+v+"%")
-				}
-			}
-		case "min_price":
-			if minPrice, ok := value.(float64); ok {
-				mainQuery = mainQuery.Gte("price_per_hour", fmt.Sprintf("%f", minPrice))
-			}
-		case "max_price":
-			if maxPrice, ok := value.(float64); ok {
-				mainQuery = mainQuery.Lte("price_per_hour", fmt.Sprintf("%f", maxPrice))
-			}
-		case "min_capacity":
-			if minCap, ok := value.(int); ok {
-				mainQuery = mainQuery.Gte("capacity", fmt.Sprintf("%d", minCap))
-			}
-		case "max_capacity":
-			if maxCap, ok := value.(int); ok {
-				mainQuery = mainQuery.Lte("capacity", fmt.Sprintf("%d", maxCap))
-			}
-		case "location":
-			if location, ok := value.(string); ok && location != "" {
-				mainQuery = mainQuery.Ilike("location", "%"+location+"%")
-			}
-		case "status":
-			if status, ok := value.(string); ok && status != "" {
-				mainQuery = mainQuery.Ilike("status", status)
-			}
-		case "name":
-			if name, ok := value.(string); ok && name != "" {
-				mainQuery = mainQuery.Ilike("name", "%"+name+"%")
-			}
-		case "description":
-			if desc, ok := value.(string); ok && desc != "" {
-				mainQuery = mainQuery.Ilike("description", "%"+desc+"%")
-			}
-		case "amenities":
-			if amenities, ok := value.([]string); ok && len(amenities) > 0 {
-				for _, amenity := range amenities {
-					mainQuery = mainQuery.Ilike("amenities", "%"+amenity+"%")
-				}
-			}
-		case "region":
-			if region, ok := value.(string); ok && region != "" {
-				mainQuery = mainQuery.Ilike("region", region)
-			}
+	var total int64
+	if withTotal {
+		var err error
+		_, total, err = applyVenueSearch(su.supabaseClient.From(VenuesTable).Select("id", "exact", false), query).Limit(1, "").Execute()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get venues count: %v", err)
 		}
 	}
 
-	// Execute main query with pagination
-	data, _, err := mainQuery.Range(offset, offset+limit-1, "").Execute()
+	// Backward pages are read in reverse order, starting next to the key
+	b := applyVenueSearch(su.supabaseClient.From(VenuesTable).Select("*", "", false), query, conditions...)
+	for _, column := range venueSortColumns(query.Sort) {
+		b = b.Order(column.name, &postgrest.OrderOpts{Ascending: column.ascending != backward})
+	}
+	data, _, err := b.Limit(limit, "").Execute()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query venues: %v", err)
 	}
 
+	venues, err := decodeVenues(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if backward {
+		slices.Reverse(venues)
+	}
+	return venues, int(total), nil
+}
+
+// venueRowPage bounds the rows asked for in one request, below the PostgREST max-rows cap
+// (1000 on Supabase by default)
+const venueRowPage = 500
+
+// decodeVenues parses the rows of a venues query
+func decodeVenues(data []byte) ([]*Venue, error) {
 	var rawVenues []map[string]interface{}
 	if err := json.Unmarshal(data, &rawVenues); err != nil {
-		return nil, 0, fmt.Errorf("failed to unmarshal venues: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal venues: %v", err)
 	}
 
 	venues := make([]*Venue, 0, len(rawVenues))
 	for _, raw := range rawVenues {
 		venue, err := convertRawToVenue(raw)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		venues = append(venues, venue)
 	}
+	return venues, nil
+}
+
+// SearchVenueIDs returns the IDs of up to limit venues matching query, in its sort order,
+// and with withTotal the number of venues matching in total
+func (su *SupabaseRepo) SearchVenueIDs(ctx context.Context, query VenueSearchQuery, limit int, withTotal bool) ([]uuid.UUID, int, error) {
+	q := applyVenueSearch(su.supabaseClient.From(VenuesTable).Select("id", countOption(withTotal), false), query)
+	data, total, err := applyVenueSort(q, query.Sort).Limit(limit, "").Execute()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search venues: %v", err)
+	}
 
-	return venues, int(total), nil
+	var rows []struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal venue IDs: %v", err)
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, int(total), nil
 }
 
 func (su *SupabaseRepo) GetVenueBySlug(ctx context.Context, slug string) (*Venue, error) {
@@ -509,7 +568,7 @@ func (su *SupabaseRepo) GetVenueBySlug(ctx context.Context, slug string) (*Venue
 		return nil, fmt.Errorf("failed to get venue: %v", err)
 	}
 	if count == 0 {
-		return nil, fmt.Errorf("venue not found")
+		return nil, ErrVenueNotFound
 	}
 
 	var rawVenues []map[string]interface{}
@@ -517,7 +576,7 @@ func (su *SupabaseRepo) GetVenueBySlug(ctx context.Context, slug string) (*Venue
 		return nil, fmt.Errorf("failed to unmarshal venue: %v", err)
 	}
 	if len(rawVenues) == 0 {
-		return nil, fmt.Errorf("venue not found")
+		return nil, ErrVenueNotFound
 	}
 
 	return convertRawToVenue(rawVenues[0])
@@ -566,3 +625,59 @@ func (su *SupabaseRepo) CreateManyVenues(ctx context.Context, venues []*Venue, h
 
 	return createdVenues, nil
 }
+
+// UpdateVenueRating stores the denormalised rating columns used for listing and sorting. It
+// writes with the service role, since reviewers and the rebuild job are never the venue's host.
+func (su *SupabaseRepo) UpdateVenueRating(ctx context.Context, venueId uuid.UUID, average float64, count int) error {
+	client, err := su.getServiceClient()
+	if err != nil {
+		return err
+	}
+
+	_, updated, err := client.From(VenuesTable).Update(map[string]interface{}{
+		"rating_average": average,
+		"rating_count":   count,
+	}, "", "exact").Eq("id", venueId.String()).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to update venue rating: %v", err)
+	}
+	if updated == 0 {
+		return ErrVenueNotFound
+	}
+
+	return nil
+}
+
+// ListDeletedVenues returns up to limit soft deleted venues deleted before the given time, oldest first
+func (su *SupabaseRepo) ListDeletedVenues(ctx context.Context, before time.Time, limit int) ([]*Venue, error) {
+	// The purge job reads every host's trash, which row level security hides from the anon key
+	client, err := su.getServiceClient()
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := client.From(VenuesTable).Select("*", "", false).
+		Lt("deleted_at", before.UTC().Format(time.RFC3339)).
+		Order("deleted_at", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted venues: %v", err)
+	}
+
+	return decodeVenues(data)
+}
+
+// ListDeletedVenuesByHost returns a host's soft deleted venues, most recently deleted first
+func (su *SupabaseRepo) ListDeletedVenuesByHost(ctx context.Context, hostId uuid.UUID, accessToken string) ([]*Venue, error) {
+	client := su.getClientWithAuth(accessToken)
+
+	data, _, err := client.From(VenuesTable).Select("*", "", false).
+		Eq("host_id", hostId.String()).
+		Not("deleted_at", "is", "null").
+		Order("deleted_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted venues: %v", err)
+	}
+
+	return decodeVenues(data)
+}
@@ -0,0 +1,290 @@
+// Package moderation screens user generated text before it is stored or published.
+//
+// Text is run through a set of checks (word lists, link and contact detection) and
+// the findings are turned into a verdict according to the policy of the surface the
+// text belongs to: allow it unchanged, mask offending terms, or hold it for review.
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrContentHeld is returned by services when text was held and the surface has no review queue
+var ErrContentHeld = errors.New("content was held for review")
+
+// Verdict is the outcome of moderating a piece of text. Higher verdicts are stricter.
+type Verdict int
+
+const (
+	VerdictAllow Verdict = iota
+	VerdictMask
+	VerdictHold
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictMask:
+		return "mask"
+	case VerdictHold:
+		return "hold"
+	default:
+		return "allow"
+	}
+}
+
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// Surface identifies where a piece of text is shown, which decides the policy applied to it
+type Surface string
+
+const (
+	SurfaceReview           Surface = "review"
+	SurfaceReviewResponse   Surface = "review_response"
+	SurfaceGuestReview      Surface = "guest_review"
+	SurfaceVenueDescription Surface = "venue_description"
+	SurfaceMessage          Surface = "message"
+	SurfaceBio              Surface = "bio"
+)
+
+// Finding kinds reported by the built-in checks
+const (
+	KindProfanity = "profanity"
+	KindSevere    = "severe"
+	KindLink      = "link"
+	KindContact   = "contact"
+)
+
+// Finding is a single match reported by a check. Start and End are byte offsets into the
+// original text; findings with Start == End are not masked.
+type Finding struct {
+	Kind  string
+	Term  string
+	Start int
+	End   int
+}
+
+// Check inspects text and reports what it found
+type Check interface {
+	Inspect(text, locale string) []Finding
+}
+
+// Reloader is implemented by checks whose data can change on disk while the server runs
+type Reloader interface {
+	ReloadIfChanged() (bool, error)
+}
+
+// Policy decides how findings on a surface become a verdict
+type Policy struct {
+	// HoldLinks holds text containing URLs or domain names
+	HoldLinks bool
+	// HoldContacts holds text containing phone numbers or email addresses
+	HoldContacts bool
+	// MaxMasked holds text needing more than this many masked terms; zero never holds
+	MaxMasked int
+}
+
+// DefaultPolicies are applied to surfaces without an explicit policy. Off-platform contact
+// details are held everywhere so bookings and payments stay on the platform.
+var DefaultPolicies = map[Surface]Policy{
+	SurfaceReview:           {HoldLinks: true, HoldContacts: true, MaxMasked: 3},
+	SurfaceReviewResponse:   {HoldLinks: true, HoldContacts: true, MaxMasked: 3},
+	SurfaceGuestReview:      {HoldLinks: true, HoldContacts: true, MaxMasked: 3},
+	SurfaceVenueDescription: {HoldLinks: true, HoldContacts: true, MaxMasked: 1},
+	SurfaceMessage:          {HoldLinks: false, HoldContacts: true},
+	SurfaceBio:              {HoldLinks: true, HoldContacts: true, MaxMasked: 1},
+}
+
+// Result is the moderated text together with the verdict and why it was reached
+type Result struct {
+	Verdict  Verdict   `json:"verdict"`
+	Text     string    `json:"text"`
+	Reasons  []string  `json:"reasons,omitempty"`
+	Findings []Finding `json:"-"`
+}
+
+// Held reports whether the text must not be published without a moderator's decision
+func (r Result) Held() bool {
+	return r.Verdict == VerdictHold
+}
+
+// Err returns ErrContentHeld wrapped with the reasons when the text was held, otherwise nil
+func (r Result) Err() error {
+	if !r.Held() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrContentHeld, strings.Join(r.Reasons, ", "))
+}
+
+// Moderator runs every check over a piece of text and applies the surface policy
+type Moderator struct {
+	checks   []Check
+	policies map[Surface]Policy
+}
+
+// New creates a moderator; a nil policies map uses DefaultPolicies
+func New(policies map[Surface]Policy, checks ...Check) *Moderator {
+	if policies == nil {
+		policies = DefaultPolicies
+	}
+	return &Moderator{
+		checks:   checks,
+		policies: policies,
+	}
+}
+
+// Moderate screens text shown on surface. The locale is read from ctx (see WithLocale).
+// A nil moderator allows everything, which keeps tools and tests free of word lists.
+func (m *Moderator) Moderate(ctx context.Context, surface Surface, text string) Result {
+	result := Result{Verdict: VerdictAllow, Text: text}
+	if m == nil || strings.TrimSpace(text) == "" {
+		return result
+	}
+
+	locale := LocaleFromContext(ctx)
+	for _, check := range m.checks {
+		result.Findings = append(result.Findings, check.Inspect(text, locale)...)
+	}
+	if len(result.Findings) == 0 {
+		return result
+	}
+
+	policy := m.policies[surface]
+	masked := 0
+	reasons := map[string]bool{}
+	addReason := func(reason string) {
+		if !reasons[reason] {
+			reasons[reason] = true
+			result.Reasons = append(result.Reasons, reason)
+		}
+	}
+	raise := func(v Verdict) {
+		if v > result.Verdict {
+			result.Verdict = v
+		}
+	}
+
+	spans := make([]Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		switch f.Kind {
+		case KindProfanity:
+			masked++
+			spans = append(spans, f)
+			raise(VerdictMask)
+			addReason("profanity")
+		case KindSevere:
+			masked++
+			spans = append(spans, f)
+			raise(VerdictHold)
+			addReason("abusive language")
+		case KindLink:
+			if policy.HoldLinks {
+				raise(VerdictHold)
+				addReason("links are not allowed")
+			}
+		case KindContact:
+			if policy.HoldContacts {
+				raise(VerdictHold)
+				addReason("contact details are not allowed")
+			}
+		}
+	}
+	if policy.MaxMasked > 0 && masked > policy.MaxMasked {
+		raise(VerdictHold)
+		addReason("excessive profanity")
+	}
+
+	result.Text = mask(text, spans)
+	return result
+}
+
+// Watch polls the reloadable checks every interval until ctx is done, so word list
+// edits take effect without a restart.
+func (m *Moderator) Watch(ctx context.Context, interval time.Duration) {
+	if m == nil || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, check := range m.checks {
+				reloader, ok := check.(Reloader)
+				if !ok {
+					continue
+				}
+				changed, err := reloader.ReloadIfChanged()
+				if err != nil {
+					fmt.Printf("[moderation] failed to reload word lists: %v\n", err)
+				} else if changed {
+					fmt.Printf("[moderation] word lists reloaded\n")
+				}
+			}
+		}
+	}
+}
+
+// mask replaces every rune inside the finding spans with '*'
+func mask(text string, spans []Finding) string {
+	if len(spans) == 0 {
+		return text
+	}
+
+	hidden := make([]bool, len(text))
+	for _, s := range spans {
+		for i := s.Start; i < s.End && i < len(text); i++ {
+			if i >= 0 {
+				hidden[i] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range text {
+		if hidden[i] {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type localeKey struct{}
+
+// WithLocale records the language of the request so locale specific word lists apply
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, NormalizeLocale(locale))
+}
+
+// LocaleFromContext returns the locale set by WithLocale, or "" if none was set
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// NormalizeLocale reduces a language tag or Accept-Language header to its primary language,
+// e.g. "fr-FR,fr;q=0.9" becomes "fr"
+func NormalizeLocale(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, ",;"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(tag))
+}
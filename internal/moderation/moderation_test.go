@@ -0,0 +1,273 @@
+package moderation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLists writes the given word list files into a temporary directory and returns the
+// path of the base list
+func writeLists(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return filepath.Join(dir, "profanity.txt")
+}
+
+func newTestWordList(t *testing.T) *WordList {
+	t.Helper()
+	file := writeLists(t, map[string]string{
+		"profanity.txt":       "# base list\nshit\nfuck\nass\nfag\n!cunt\n\ntwo words\n",
+		"profanity.fr.txt":    "merde\n",
+		"profanity.de.txt":    "scheiße\n",
+		"profanity.allow.txt": "scunthorpe\nfags\n",
+	})
+	words, err := NewWordList(WordListConfig{File: file, Words: "bollocks, wanker"})
+	if err != nil {
+		t.Fatalf("NewWordList: %v", err)
+	}
+	return words
+}
+
+func TestWordListInspect(t *testing.T) {
+	words := newTestWordList(t)
+
+	tests := []struct {
+		name   string
+		text   string
+		locale string
+		want   []Finding
+	}{
+		{"plain", "this is shit", "", []Finding{{KindProfanity, "shit", 8, 12}}},
+		{"capitals", "SHIT happens", "", []Finding{{KindProfanity, "shit", 0, 4}}},
+		{"leetspeak", "sh1t", "", []Finding{{KindProfanity, "shit", 0, 4}}},
+		{"leetspeak symbols", "$h!t and @ss", "", []Finding{{KindProfanity, "shit", 0, 4}, {KindProfanity, "ass", 9, 12}}},
+		{"dotted", "f.u.c.k off", "", []Finding{{KindProfanity, "fuck", 0, 7}}},
+		{"spaced letters", "oh f u c k it", "", []Finding{{KindProfanity, "fuck", 3, 10}}},
+		{"short letter runs are left alone", "a b c", "", nil},
+		{"repeated letters", "fuuuuuck", "", []Finding{{KindProfanity, "fuck", 0, 8}}},
+		{"suffix ing", "fucking great", "", []Finding{{KindProfanity, "fuck", 0, 7}}},
+		{"suffix y", "a shitty room", "", []Finding{{KindProfanity, "shit", 2, 8}}},
+		{"suffix es", "smart asses", "", []Finding{{KindProfanity, "ass", 6, 11}}},
+		{"edge punctuation", "\"shit!\"", "", []Finding{{KindProfanity, "shit", 1, 5}}},
+		{"glued by punctuation", "great,shit", "", []Finding{{KindProfanity, "shit", 0, 10}}},
+		{"severe terms", "you cunt", "", []Finding{{KindSevere, "cunt", 4, 8}}},
+		{"extra words from config", "total bollocks", "", []Finding{{KindProfanity, "bollocks", 6, 14}}},
+		{"whole words only", "a classic class assignment", "", nil},
+		{"allowlisted word", "Scunthorpe United", "", nil},
+		{"allowlisted inflection", "a pack of fags", "", nil},
+		{"multi-word lines are skipped", "two words", "", nil},
+		{"locale list applies to its locale", "quelle merde", "fr", []Finding{{KindProfanity, "merde", 7, 12}}},
+		{"locale list ignored for other locales", "quelle merde", "en", nil},
+		{"allow list is not a locale", "scunthorpe", "allow", nil},
+		{"multi-byte term", "So eine Scheiße!", "de", []Finding{{KindProfanity, "scheiße", 8, 16}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := words.Inspect(tt.text, tt.locale)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Inspect(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Inspect(%q)[%d] = %+v, want %+v", tt.text, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWordListFiles(t *testing.T) {
+	file := writeLists(t, map[string]string{
+		"profanity.txt":       "shit\n",
+		"profanity.fr.txt":    "merde\n",
+		"profanity.allow.txt": "scunthorpe\n",
+		"other.fr.txt":        "putain\n",
+	})
+	words, err := NewWordList(WordListConfig{File: file})
+	if err != nil {
+		t.Fatalf("NewWordList: %v", err)
+	}
+
+	files, err := words.files()
+	if err != nil {
+		t.Fatalf("files: %v", err)
+	}
+	want := []string{file, filepath.Join(filepath.Dir(file), "profanity.allow.txt"), filepath.Join(filepath.Dir(file), "profanity.fr.txt")}
+	if len(files) != len(want) {
+		t.Fatalf("files() = %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("files()[%d] = %s, want %s", i, files[i], want[i])
+		}
+	}
+
+	if got := words.Inspect("putain", "fr"); len(got) != 0 {
+		t.Errorf("lists of another base name were loaded: %+v", got)
+	}
+}
+
+func TestWordListMissingFile(t *testing.T) {
+	words, err := NewWordList(WordListConfig{File: filepath.Join(t.TempDir(), "profanity.txt"), Words: "shit"})
+	if err != nil {
+		t.Fatalf("a missing base list should not fail: %v", err)
+	}
+	if got := words.Inspect("shit", ""); len(got) != 1 {
+		t.Errorf("configured words were not loaded: %+v", got)
+	}
+}
+
+func TestWordListReloadIfChanged(t *testing.T) {
+	file := writeLists(t, map[string]string{"profanity.txt": "shit\n"})
+	words, err := NewWordList(WordListConfig{File: file})
+	if err != nil {
+		t.Fatalf("NewWordList: %v", err)
+	}
+
+	if changed, err := words.ReloadIfChanged(); err != nil || changed {
+		t.Fatalf("ReloadIfChanged() = %v, %v before any change", changed, err)
+	}
+
+	locale := filepath.Join(filepath.Dir(file), "profanity.fr.txt")
+	if err := os.WriteFile(locale, []byte("merde\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := words.ReloadIfChanged(); err != nil || !changed {
+		t.Fatalf("ReloadIfChanged() = %v, %v after adding a locale list", changed, err)
+	}
+	if got := words.Inspect("merde", "fr"); len(got) != 1 {
+		t.Errorf("new locale list was not loaded: %+v", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		spans []Finding
+		want  string
+	}{
+		{"no spans", "hello", nil, "hello"},
+		{"ascii", "this is shit", []Finding{{Start: 8, End: 12}}, "this is ****"},
+		{"multi-byte around the span", "naïve shit señor", []Finding{{Start: 7, End: 11}}, "naïve **** señor"},
+		{"multi-byte inside the span", "So eine Scheiße!", []Finding{{Start: 8, End: 16}}, "So eine *******!"},
+		{"overlapping spans", "abcdef", []Finding{{Start: 1, End: 3}, {Start: 2, End: 5}}, "a****f"},
+		{"out of range span", "abc", []Finding{{Start: -1, End: 10}}, "***"},
+		{"empty span", "abc", []Finding{{Start: 1, End: 1}}, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mask(tt.text, tt.spans); got != tt.want {
+				t.Errorf("mask(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContactCheck(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"call me on 024 123 4567", true},
+		{"+233 24 123 4567 any time", true},
+		{"zero two four one two three four five six seven", true},
+		{"mail me at host@example.com", true},
+		{"host (at) example (dot) com", true},
+		{"the party is on 2024-05-12 at 10:30", false},
+		{"booking ref AB1234567890", false},
+		{"someone often mentions one or two things", false},
+	}
+	for _, tt := range tests {
+		got := len(ContactCheck{}.Inspect(tt.text, "")) > 0
+		if got != tt.want {
+			t.Errorf("ContactCheck.Inspect(%q) found contact = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLinkCheck(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"see https://example.com/venue", true},
+		{"book at www.example.org", true},
+		{"find us on example [dot] com", true},
+		{"a great venue. come early", false},
+	}
+	for _, tt := range tests {
+		got := len(LinkCheck{}.Inspect(tt.text, "")) > 0
+		if got != tt.want {
+			t.Errorf("LinkCheck.Inspect(%q) found link = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestModerate(t *testing.T) {
+	words := newTestWordList(t)
+	m := New(nil, words, LinkCheck{}, ContactCheck{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		surface Surface
+		text    string
+		verdict Verdict
+		want    string
+	}{
+		{"clean text", ctx, SurfaceReview, "lovely venue", VerdictAllow, "lovely venue"},
+		{"masked profanity", ctx, SurfaceReview, "the sh1t sound system", VerdictMask, "the **** sound system"},
+		{"severe terms hold", ctx, SurfaceReview, "the host is a cunt", VerdictHold, "the host is a ****"},
+		{"too much profanity", ctx, SurfaceVenueDescription, "shit shit", VerdictHold, "**** ****"},
+		{"links held on reviews", ctx, SurfaceReview, "see www.example.com", VerdictHold, "see www.example.com"},
+		{"links allowed in messages", ctx, SurfaceMessage, "see www.example.com", VerdictAllow, "see www.example.com"},
+		{"contacts held in messages", ctx, SurfaceMessage, "call 024 123 4567", VerdictHold, "call 024 123 4567"},
+		{"locale from context", WithLocale(ctx, "fr-FR,fr;q=0.9"), SurfaceReview, "quelle merde", VerdictMask, "quelle *****"},
+		{"no locale", ctx, SurfaceReview, "quelle merde", VerdictAllow, "quelle merde"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := m.Moderate(tt.ctx, tt.surface, tt.text)
+			if result.Verdict != tt.verdict {
+				t.Errorf("verdict = %s, want %s (reasons %v)", result.Verdict, tt.verdict, result.Reasons)
+			}
+			if result.Text != tt.want {
+				t.Errorf("text = %q, want %q", result.Text, tt.want)
+			}
+			if (result.Err() != nil) != result.Held() {
+				t.Errorf("Err() = %v for verdict %s", result.Err(), result.Verdict)
+			}
+		})
+	}
+
+	var nilModerator *Moderator
+	if result := nilModerator.Moderate(ctx, SurfaceReview, "shit"); result.Verdict != VerdictAllow || result.Text != "shit" {
+		t.Errorf("nil moderator = %+v, want the text allowed unchanged", result)
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"":               "",
+		"fr":             "fr",
+		"fr-FR":          "fr",
+		"pt_BR":          "pt",
+		"EN-gb,en;q=0.8": "en",
+		" de ; q=0.5":    "de",
+		"fr-FR,fr;q=0.9": "fr",
+	}
+	for tag, want := range tests {
+		if got := NormalizeLocale(tag); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// URLs, bare domains and the usual "example [dot] com" obfuscations
+	reLink  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9][a-z0-9-]*(?:\.|\s*\[\.\]\s*|\s*\(\.\)\s*|\s*\[dot\]\s*|\s*\(dot\)\s*)(?:com|net|org|io|co|me|gh|info|biz|xyz|app|site|online|shop|link)\b`)
+	reEmail = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+\s*(?:@|\(at\)|\[at\])\s*[a-z0-9-]+(?:\.|\s*\(dot\)\s*|\s*\[dot\]\s*)[a-z]{2,}\b`)
+	// Nine to fifteen digits with at most two separator characters between them. Matches are
+	// then checked by phoneLike, which rules out dates, times and digits inside longer tokens.
+	rePhone = regexp.MustCompile(`\+?\d(?:[\s().-]{0,2}\d){8,14}`)
+	// Dates written year first or year last, e.g. 2024-05-12 or 12.05.2024
+	reDate = regexp.MustCompile(`\d{4}[-./]\d{1,2}[-./]\d{1,2}|\d{1,2}[-./]\d{1,2}[-./]\d{2,4}`)
+	// digitWord spells out a digit as a whole word, so "zero two four ..." is caught like
+	// "024..." without touching words such as "someone" or "often"
+	reDigitWord = regexp.MustCompile(`\b(?:zero|one|two|three|four|five|six|seven|eight|nine)\b`)
+)
+
+var digitValues = map[string]string{
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+}
+
+// spellDigits replaces spelled out digits with the digits themselves
+func spellDigits(text string) string {
+	return reDigitWord.ReplaceAllStringFunc(text, func(word string) string {
+		return digitValues[word]
+	})
+}
+
+// phoneLike reports whether the rePhone match at text[start:end] is shaped like a phone
+// number rather than a date, a time or part of a longer code
+func phoneLike(text string, start, end int) bool {
+	if start > 0 && isWordByte(text[start-1]) {
+		return false
+	}
+	if end < len(text) && (isWordByte(text[end]) || text[end] == ':') {
+		return false
+	}
+	return !reDate.MatchString(text[start:end])
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// LinkCheck reports URLs and domain names
+type LinkCheck struct{}
+
+func (LinkCheck) Inspect(text, _ string) []Finding {
+	var findings []Finding
+	for _, loc := range reLink.FindAllStringIndex(text, -1) {
+		// Links are held rather than masked, so the span is left empty
+		findings = append(findings, Finding{Kind: KindLink, Term: text[loc[0]:loc[1]]})
+	}
+	return findings
+}
+
+// ContactCheck reports phone numbers and email addresses, including spelled-out digits
+type ContactCheck struct{}
+
+func (ContactCheck) Inspect(text, _ string) []Finding {
+	var findings []Finding
+	for _, match := range reEmail.FindAllString(text, -1) {
+		findings = append(findings, Finding{Kind: KindContact, Term: match})
+	}
+	spelled := spellDigits(strings.ToLower(text))
+	for _, loc := range rePhone.FindAllStringIndex(spelled, -1) {
+		if phoneLike(spelled, loc[0], loc[1]) {
+			findings = append(findings, Finding{Kind: KindContact, Term: spelled[loc[0]:loc[1]]})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,417 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+)
+
+// WordListConfig locates the profanity word lists.
+//
+// File is the base list applied to every locale. Next to it, "<name>.<locale>.txt" files
+// add terms for a single locale (e.g. profanity.fr.txt) and "<name>.allow.txt" lists terms
+// that must never be flagged. Lists hold one term per line; "#" starts a comment and a
+// leading "!" marks a severe term, which holds the text instead of only masking it.
+type WordListConfig struct {
+	File string
+	// Words are extra comma separated terms for every locale, typically from PROFANITY_WORDS
+	Words string
+}
+
+type severity int
+
+const (
+	severityMask severity = iota + 1
+	severityHold
+)
+
+// minStemLength keeps suffix stripping from turning short words into listed terms
+const minStemLength = 3
+
+// suffixes are stripped when looking up inflected forms ("shitty", "fucking"), longest first
+var suffixes = []string{"ing", "ers", "es", "ed", "er", "in", "s", "y"}
+
+// leet maps common character substitutions back to the letter they stand for
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'!': 'i',
+	'3': 'e',
+	'4': 'a',
+	'@': 'a',
+	'5': 's',
+	'$': 's',
+	'7': 't',
+	'+': 't',
+	'8': 'b',
+	'|': 'l',
+}
+
+// edgePunctuation is trimmed from tokens before matching so "shit!" is not read as "shiti"
+const edgePunctuation = ".,?;:\"'()[]{}<>*-_~"
+
+type wordListState struct {
+	terms   map[string]severity
+	locales map[string]map[string]severity
+	allow   map[string]bool
+}
+
+// WordList is a hot-reloadable profanity check that sees through leetspeak and separators
+type WordList struct {
+	cfg   WordListConfig
+	mu    sync.Mutex
+	state atomic.Pointer[wordListState]
+	stamp string
+}
+
+// NewWordList loads the configured lists. A missing base file leaves the list empty;
+// the returned WordList is usable even when an error is reported.
+func NewWordList(cfg WordListConfig) (*WordList, error) {
+	w := &WordList{cfg: cfg}
+	w.state.Store(&wordListState{
+		terms:   map[string]severity{},
+		locales: map[string]map[string]severity{},
+		allow:   map[string]bool{},
+	})
+	return w, w.Reload()
+}
+
+// Reload re-reads every list from disk and swaps them in atomically
+func (w *WordList) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stamp, err := w.fingerprint()
+	if err != nil {
+		return err
+	}
+	return w.load(stamp)
+}
+
+// ReloadIfChanged reloads the lists when any of the files was added, removed or modified
+func (w *WordList) ReloadIfChanged() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stamp, err := w.fingerprint()
+	if err != nil {
+		return false, err
+	}
+	if stamp == w.stamp {
+		return false, nil
+	}
+	if err := w.load(stamp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// files returns the base list followed by the locale and allow lists found beside it
+func (w *WordList) files() ([]string, error) {
+	if w.cfg.File == "" {
+		return nil, nil
+	}
+
+	ext := filepath.Ext(w.cfg.File)
+	pattern := strings.TrimSuffix(w.cfg.File, ext) + ".*" + ext
+	extra, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid word list path: %v", err)
+	}
+	sort.Strings(extra)
+	return append([]string{w.cfg.File}, extra...), nil
+}
+
+func (w *WordList) fingerprint() (string, error) {
+	files, err := w.files()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return "", fmt.Errorf("failed to stat word list '%s': %v", path, err)
+		}
+		fmt.Fprintf(&b, "%s|%d|%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+func (w *WordList) load(stamp string) error {
+	files, err := w.files()
+	if err != nil {
+		return err
+	}
+
+	next := &wordListState{
+		terms:   map[string]severity{},
+		locales: map[string]map[string]severity{},
+		allow:   map[string]bool{},
+	}
+
+	ext := filepath.Ext(w.cfg.File)
+	prefix := strings.TrimSuffix(filepath.Base(w.cfg.File), ext) + "."
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to read word list '%s': %v", path, err)
+		}
+		lines := strings.Split(string(data), "\n")
+
+		if path == w.cfg.File {
+			addTerms(next.terms, lines)
+			continue
+		}
+		locale := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ext)
+		if locale == "allow" {
+			for term := range parseTerms(lines) {
+				next.allow[term] = true
+			}
+			continue
+		}
+		locale = NormalizeLocale(locale)
+		if next.locales[locale] == nil {
+			next.locales[locale] = map[string]severity{}
+		}
+		addTerms(next.locales[locale], lines)
+	}
+
+	if w.cfg.Words != "" {
+		addTerms(next.terms, strings.Split(w.cfg.Words, ","))
+	}
+
+	w.state.Store(next)
+	w.stamp = stamp
+	return nil
+}
+
+func addTerms(dst map[string]severity, lines []string) {
+	for term, sev := range parseTerms(lines) {
+		if sev > dst[term] {
+			dst[term] = sev
+		}
+	}
+}
+
+// parseTerms normalises list entries, skipping blanks, comments and multi-word lines
+func parseTerms(lines []string) map[string]severity {
+	out := map[string]severity{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sev := severityMask
+		if strings.HasPrefix(line, "!") {
+			sev = severityHold
+			line = strings.TrimSpace(line[1:])
+		}
+		if strings.ContainsFunc(line, unicode.IsSpace) {
+			continue
+		}
+		if term := normalizeTerm(line); term != "" {
+			out[term] = sev
+		}
+	}
+	return out
+}
+
+// Inspect reports every listed term in text, matching whole words after normalisation
+func (w *WordList) Inspect(text, locale string) []Finding {
+	state := w.state.Load()
+	if state == nil || (len(state.terms) == 0 && len(state.locales[locale]) == 0) {
+		return nil
+	}
+
+	var findings []Finding
+	report := func(term string, sev severity, start, end int) {
+		kind := KindProfanity
+		if sev == severityHold {
+			kind = KindSevere
+		}
+		findings = append(findings, Finding{Kind: kind, Term: term, Start: start, End: end})
+	}
+
+	// Runs of single letters ("f u c k") are joined and matched as one word
+	var run strings.Builder
+	runStart, runEnd, runLen := 0, 0, 0
+	flushRun := func() {
+		if runLen >= 3 {
+			if term, sev := state.match(run.String(), locale); sev > 0 {
+				report(term, sev, runStart, runEnd)
+			}
+		}
+		run.Reset()
+		runLen = 0
+	}
+
+	for _, tok := range tokenize(text) {
+		normalized := normalizeTerm(tok.text)
+		if len([]rune(normalized)) == 1 {
+			if runLen == 0 {
+				runStart = tok.start
+			}
+			run.WriteString(normalized)
+			runEnd = tok.end
+			runLen++
+			continue
+		}
+		flushRun()
+
+		if normalized == "" || state.allow[normalized] {
+			continue
+		}
+		if term, sev := state.match(normalized, locale); sev > 0 {
+			report(term, sev, tok.start, tok.end)
+			continue
+		}
+		// Words glued together by punctuation ("great,shit") are checked one by one
+		for _, part := range strings.FieldsFunc(tok.text, isSeparator) {
+			part = normalizeTerm(part)
+			if part == "" || state.allow[part] {
+				continue
+			}
+			if term, sev := state.match(part, locale); sev > 0 {
+				report(term, sev, tok.start, tok.end)
+				break
+			}
+		}
+	}
+	flushRun()
+
+	return findings
+}
+
+// match looks a normalised word up in the base and locale lists, trying squeezed
+// repeats ("fuuuck") and common inflections
+func (s *wordListState) match(word, locale string) (string, severity) {
+	lookup := func(candidate string) (string, severity) {
+		if sev := s.terms[candidate]; sev > 0 {
+			return candidate, sev
+		}
+		if sev := s.locales[locale][candidate]; sev > 0 {
+			return candidate, sev
+		}
+		return "", 0
+	}
+	try := func(candidate string) (string, severity) {
+		if term, sev := lookup(candidate); sev > 0 {
+			return term, sev
+		}
+		if squeezed := squeeze(candidate, 2); squeezed != candidate {
+			if term, sev := lookup(squeezed); sev > 0 {
+				return term, sev
+			}
+		}
+		if single := squeeze(candidate, 1); single != candidate {
+			if term, sev := lookup(single); sev > 0 {
+				return term, sev
+			}
+		}
+		return "", 0
+	}
+
+	if term, sev := try(word); sev > 0 {
+		return term, sev
+	}
+	for _, suffix := range suffixes {
+		stem := strings.TrimSuffix(word, suffix)
+		if stem == word || len(stem) < minStemLength || s.allow[stem] {
+			continue
+		}
+		if term, sev := try(stem); sev > 0 {
+			return term, sev
+		}
+	}
+	return "", 0
+}
+
+type token struct {
+	text       string
+	start, end int
+}
+
+// tokenize splits text on whitespace and trims edge punctuation, keeping byte offsets
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	emit := func(end int) {
+		raw := text[start:end]
+		trimmed := strings.TrimLeft(raw, edgePunctuation)
+		s := start + len(raw) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, edgePunctuation+"!")
+		if trimmed != "" {
+			tokens = append(tokens, token{text: trimmed, start: s, end: s + len(trimmed)})
+		}
+	}
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				emit(i)
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		emit(len(text))
+	}
+	return tokens
+}
+
+// normalizeTerm lowercases a word, undoes leetspeak and drops separators, so "F.u.c.k"
+// and "sh1t" compare equal to their plain spelling
+func normalizeTerm(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if mapped, ok := leet[r]; ok {
+			r = mapped
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isSeparator(r rune) bool {
+	if _, ok := leet[r]; ok {
+		return false
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// squeeze shortens runs of the same letter to at most max letters
+func squeeze(s string, max int) string {
+	var b strings.Builder
+	var prev rune
+	count := 0
+	for _, r := range s {
+		if r == prev {
+			count++
+		} else {
+			prev, count = r, 1
+		}
+		if count <= max {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
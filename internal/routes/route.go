@@ -7,6 +7,7 @@ import (
 	"github.com/joshua-takyi/ww/internal/handlers"
 	"github.com/joshua-takyi/ww/internal/helpers"
 	"github.com/joshua-takyi/ww/internal/middleware"
+	"github.com/joshua-takyi/ww/internal/storage"
 )
 
 func SetupRoutes(container *container.Container) *gin.Engine {
@@ -19,7 +20,7 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID", "X-Locale"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}))
@@ -28,8 +29,16 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 	r.Use(middleware.RequestID())
 	r.Use(middleware.StructuredLogger(container.Logger))
 	r.Use(middleware.ErrorHandler(container.Logger))
+	r.Use(middleware.Locale())
 	r.Use(gin.Recovery())
 
+	// Files kept by the local media store are served by the API itself
+	if local, ok := container.Media.(*storage.LocalStore); ok {
+		r.GET(local.Prefix()+"/*filepath", gin.WrapH(local))
+		r.HEAD(local.Prefix()+"/*filepath", gin.WrapH(local))
+		r.POST(local.Prefix()+"/*filepath", gin.WrapH(local))
+	}
+
 	v1 := r.Group("/api/v1")
 	{
 		// Health check
@@ -57,6 +66,9 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 		v1.GET("/venues", handlers.ListVenues(container.VenueService))
 		v1.GET("/venues/slug/:slug", handlers.GetVenueBySlug(container.VenueService))
 		v1.POST("/venues/:id/view", handlers.TrackVenueView(container.VenueService)) // ADD THIS
+		v1.GET("/venues/:id/reviews", handlers.GetVenueReviews(container.ReviewService))
+		v1.GET("/search/suggest", handlers.SuggestSearches(container.VenueService))
+		v1.GET("/amenities", handlers.ListAmenities())
 
 	}
 
@@ -97,7 +109,7 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 		userRoutes.GET("/:id", handlers.GetUser(container.UserService))
 		userRoutes.PATCH("/:id", handlers.UpdateUser(container.UserService))
 		userRoutes.DELETE("/:id", handlers.DeleteUser(container.UserService))
-		userRoutes.PATCH("/avatar/:id", handlers.UploadAvatar(container.UserService, container.Cloudinary))
+		userRoutes.PATCH("/avatar/:id", handlers.UploadAvatar(container.UserService))
 	}
 
 	// Future routes for other services
@@ -110,9 +122,12 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 	venueRoutes := protected.Group("/venues")
 	{
 		venueRoutes.POST("/", handlers.CreateVenueHandler(container.VenueService))
+		venueRoutes.POST("/drafts", handlers.CreateVenueDraft(container.VenueService))
 		venueRoutes.GET("/", handlers.ListVenues(container.VenueService))
 		venueRoutes.GET("/:id", handlers.ListVenueByID(container.VenueService))
 		venueRoutes.DELETE("/:id", handlers.DeleteVenue(container.VenueService))
+		venueRoutes.GET("/deleted", handlers.ListDeletedVenues(container.VenueService))
+		venueRoutes.POST("/:id/restore", handlers.RestoreVenue(container.VenueService))
 		venueRoutes.GET("/host-venues/:host_id", handlers.ListVenuesByHost(container.VenueService))
 		venueRoutes.POST("/many", handlers.CreateManyVenues(container.VenueService))
 		venueRoutes.GET("/:id/stats", handlers.GetVenueViewStats(container.VenueService))
@@ -123,15 +138,52 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 		venueRoutes.GET("/host/:host_id/views", handlers.GetHostViewHistory(container.VenueService))
 
 		// venueRoutes.GET("/search", handlers.QueryVenues(container.VenueService))
-		// venueRoutes.PATCH("/:id", handlers.UpdateVenue(container.VenueService))
+		venueRoutes.PATCH("/:id", handlers.UpdateVenue(container.VenueService))
+		venueRoutes.POST("/:id/images", handlers.UploadVenueImages(container.VenueService))
+		venueRoutes.POST("/:id/images/uploads", handlers.IssueVenueImageUploads(container.VenueService))
+		venueRoutes.POST("/:id/images/confirm", handlers.ConfirmVenueImageUploads(container.VenueService))
+		venueRoutes.PUT("/:id/images/order", handlers.ReorderVenueImages(container.VenueService))
+		venueRoutes.PUT("/:id/images/cover", handlers.SetVenueCoverImage(container.VenueService))
+		venueRoutes.DELETE("/:id/images/:index", handlers.DeleteVenueImage(container.VenueService))
+		venueRoutes.POST("/:id/deactivate", handlers.DeactivateVenue(container.VenueService))
+		venueRoutes.POST("/:id/reactivate", handlers.ReactivateVenue(container.VenueService))
+		venueRoutes.GET("/:id/status-history", handlers.GetVenueStatusHistory(container.VenueService))
+		venueRoutes.GET("/:id/completeness", handlers.GetVenueCompleteness(container.VenueService))
+		venueRoutes.POST("/:id/submit", handlers.SubmitVenueDraft(container.VenueService))
 
 	}
-	//
-	// reviewRoutes := v1.Group("/reviews")
-	// {
-	//     reviewRoutes.POST("/", handlers.CreateReview(container.ReviewService))
-	//     reviewRoutes.GET("/", handlers.ListReviews(container.ReviewService))
-	// }
+
+	reviewRoutes := protected.Group("/reviews")
+	{
+		reviewRoutes.POST("/", handlers.CreateReview(container.ReviewService))
+		reviewRoutes.GET("/me", handlers.GetMyReviews(container.ReviewService))
+		reviewRoutes.PATCH("/:id", handlers.UpdateReview(container.ReviewService))
+		reviewRoutes.DELETE("/:id", handlers.DeleteReview(container.ReviewService))
+		reviewRoutes.POST("/:id/report", handlers.ReportReview(container.ReviewService))
+		reviewRoutes.PUT("/:id/response", handlers.RespondToReview(container.ReviewService))
+		reviewRoutes.DELETE("/:id/response", handlers.DeleteReviewResponse(container.ReviewService))
+		reviewRoutes.POST("/:id/photos", handlers.UploadReviewPhotos(container.ReviewService))
+		reviewRoutes.DELETE("/:id/photos/:index", handlers.DeleteReviewPhoto(container.ReviewService))
+	}
+
+	guestReviewRoutes := protected.Group("/guest-reviews")
+	{
+		guestReviewRoutes.POST("/", handlers.CreateGuestReview(container.ReviewService))
+		guestReviewRoutes.GET("/me", handlers.GetMyGuestReviews(container.ReviewService))
+		guestReviewRoutes.GET("/written", handlers.GetWrittenGuestReviews(container.ReviewService))
+	}
+	protected.GET("/guests/:id/reputation", handlers.GetGuestReputation(container.ReviewService))
+
+	// Admin routes (role checked in handlers)
+	adminRoutes := protected.Group("/admin")
+	{
+		adminRoutes.GET("/reviews", handlers.GetReviewModerationQueue(container.ReviewService))
+		adminRoutes.POST("/reviews/:id/approve", handlers.ModerateReview(container.ReviewService, true))
+		adminRoutes.POST("/reviews/:id/reject", handlers.ModerateReview(container.ReviewService, false))
+		adminRoutes.GET("/venues", handlers.GetVenueReviewQueue(container.VenueService))
+		adminRoutes.POST("/venues/:id/approve", handlers.ReviewVenue(container.VenueService, true))
+		adminRoutes.POST("/venues/:id/reject", handlers.ReviewVenue(container.VenueService, false))
+	}
 
 	{
 		favRoutes := protected.Group("/favourites")
@@ -140,5 +192,20 @@ func SetupRoutes(container *container.Container) *gin.Engine {
 		favRoutes.DELETE("/:id", handlers.RemoveFromFavourite(container.FavouritesService))
 	}
 
+	savedSearchRoutes := protected.Group("/saved-searches")
+	{
+		savedSearchRoutes.POST("/", handlers.CreateSavedSearch(container.SavedSearchService))
+		savedSearchRoutes.GET("/", handlers.GetSavedSearches(container.SavedSearchService))
+		savedSearchRoutes.PATCH("/:id", handlers.RenameSavedSearch(container.SavedSearchService))
+		savedSearchRoutes.DELETE("/:id", handlers.DeleteSavedSearch(container.SavedSearchService))
+	}
+
+	notificationRoutes := protected.Group("/notifications")
+	{
+		notificationRoutes.GET("/", handlers.GetNotifications(container.NotificationService))
+		notificationRoutes.GET("/unread-count", handlers.GetUnreadNotificationCount(container.NotificationService))
+		notificationRoutes.POST("/:id/read", handlers.MarkNotificationRead(container.NotificationService))
+	}
+
 	return r
 }
@@ -0,0 +1,309 @@
+// Package search is a small in-memory full-text index for ranking documents against free text.
+//
+// Documents are split into weighted fields, tokenized, stemmed and scored with BM25 over the
+// weighted field frequencies. Queries match every word they contain, tolerate small typos in
+// longer words and may quote phrases that must appear word for word. The index is rebuilt
+// wholesale from its source of truth, which keeps it simple and makes stale entries harmless.
+// A Suggester completes what users have typed so far from the same kind of snapshot.
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters: k1 saturates repeated terms, b normalises for field length
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+const (
+	// fuzzyWeight scales the score of words that only matched after typo correction
+	fuzzyWeight = 0.7
+	// phraseBonus is added per quoted phrase, times the weight of the field it was found in
+	phraseBonus = 1.5
+)
+
+// Field is a named part of a document; matches in heavier fields rank higher
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Document is indexed under ID with its text keyed by field name
+type Document struct {
+	ID     string
+	Fields map[string]string
+}
+
+// Hit is a document matching a query. Highlights holds a snippet of each field that matched,
+// HTML escaped with the matched words wrapped in <mark>.
+type Hit struct {
+	ID         string
+	Score      float64
+	Highlights map[string]string
+}
+
+type posting struct {
+	doc       int
+	field     int
+	positions []int
+}
+
+type indexedDoc struct {
+	id     string
+	text   []string
+	length []int
+}
+
+// snapshot is an immutable build of the index; rebuilds swap in a new one
+type snapshot struct {
+	docs     []indexedDoc
+	postings map[string][]posting
+	// words maps every indexed word to its stem, for typo correction
+	words  map[string]string
+	avgLen []float64
+}
+
+// Index is safe for concurrent use; searches keep running on the previous build while a
+// new one is made
+type Index struct {
+	fields []Field
+	mu     sync.RWMutex
+	snap   *snapshot
+}
+
+func NewIndex(fields []Field) *Index {
+	return &Index{fields: fields, snap: &snapshot{postings: map[string][]posting{}, words: map[string]string{}}}
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.snap.docs)
+}
+
+// Rebuild replaces the contents of the index with docs
+func (ix *Index) Rebuild(docs []Document) {
+	snap := &snapshot{
+		docs:     make([]indexedDoc, 0, len(docs)),
+		postings: map[string][]posting{},
+		words:    map[string]string{},
+		avgLen:   make([]float64, len(ix.fields)),
+	}
+
+	for d, doc := range docs {
+		entry := indexedDoc{id: doc.ID, text: make([]string, len(ix.fields)), length: make([]int, len(ix.fields))}
+		for f, field := range ix.fields {
+			text := doc.Fields[field.Name]
+			entry.text[f] = text
+			tokens := tokenize(text)
+			entry.length[f] = len(tokens)
+			snap.avgLen[f] += float64(len(tokens))
+
+			positions := map[string][]int{}
+			for i, token := range tokens {
+				stemmed := stem(token.word)
+				snap.words[token.word] = stemmed
+				positions[stemmed] = append(positions[stemmed], i)
+			}
+			for term, at := range positions {
+				snap.postings[term] = append(snap.postings[term], posting{doc: d, field: f, positions: at})
+			}
+		}
+		snap.docs = append(snap.docs, entry)
+	}
+	if len(docs) > 0 {
+		for f := range snap.avgLen {
+			snap.avgLen[f] /= float64(len(docs))
+		}
+	}
+
+	ix.mu.Lock()
+	ix.snap = snap
+	ix.mu.Unlock()
+}
+
+// Search returns up to limit documents matching query, best first. Documents must contain
+// every word of the query apart from stop words, and every quoted phrase.
+func (ix *Index) Search(query string, limit int) []Hit {
+	ix.mu.RLock()
+	snap := ix.snap
+	ix.mu.RUnlock()
+
+	parsed := parseQuery(query)
+	if len(parsed.terms) == 0 && len(parsed.phrases) == 0 {
+		return nil
+	}
+
+	scores := map[int]float64{}
+	// matched holds the stems each document matched, to highlight them
+	matched := map[int]map[string]bool{}
+	for i, word := range parsed.terms {
+		termScores := map[int]float64{}
+		for term, weight := range snap.expand(word) {
+			for doc, score := range ix.scoreTerm(snap, term) {
+				if score*weight > termScores[doc] {
+					termScores[doc] = score * weight
+				}
+				if matched[doc] == nil {
+					matched[doc] = map[string]bool{}
+				}
+				matched[doc][term] = true
+			}
+		}
+		// Every word is required, so the candidates shrink with each one
+		if i == 0 {
+			scores = termScores
+			continue
+		}
+		for doc := range scores {
+			if score, ok := termScores[doc]; ok {
+				scores[doc] += score
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	for i, phrase := range parsed.phrases {
+		found := ix.matchPhrase(snap, phrase)
+		if i == 0 && len(parsed.terms) == 0 {
+			scores = map[int]float64{}
+			for doc := range found {
+				scores[doc] = 0
+			}
+		}
+		for doc := range scores {
+			weight, ok := found[doc]
+			if !ok {
+				delete(scores, doc)
+				continue
+			}
+			scores[doc] += phraseBonus * weight
+			if matched[doc] == nil {
+				matched[doc] = map[string]bool{}
+			}
+			for _, term := range phrase {
+				// Loose stop words would light up all over the snippet
+				if !stopWords[term] {
+					matched[doc][term] = true
+				}
+			}
+		}
+	}
+
+	ranked := make([]int, 0, len(scores))
+	for doc := range scores {
+		ranked = append(ranked, doc)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return snap.docs[a].id < snap.docs[b].id
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	hits := make([]Hit, len(ranked))
+	for i, doc := range ranked {
+		hits[i] = Hit{ID: snap.docs[doc].id, Score: scores[doc], Highlights: ix.highlight(snap.docs[doc], matched[doc])}
+	}
+	return hits
+}
+
+// scoreTerm scores every document containing term
+func (ix *Index) scoreTerm(snap *snapshot, term string) map[int]float64 {
+	postings := snap.postings[term]
+	if len(postings) == 0 {
+		return nil
+	}
+
+	// Weighted term frequency per document, normalised by field length
+	weighted := map[int]float64{}
+	for _, p := range postings {
+		length := float64(snap.docs[p.doc].length[p.field])
+		norm := 1 - bm25B
+		if avg := snap.avgLen[p.field]; avg > 0 {
+			norm += bm25B * length / avg
+		}
+		weighted[p.doc] += ix.fields[p.field].Weight * float64(len(p.positions)) / norm
+	}
+
+	n := float64(len(snap.docs))
+	df := float64(len(weighted))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	scores := make(map[int]float64, len(weighted))
+	for doc, tf := range weighted {
+		scores[doc] = idf * tf * (bm25K1 + 1) / (tf + bm25K1)
+	}
+	return scores
+}
+
+// matchPhrase finds the documents containing the stems of phrase in a row within one field,
+// with the weight of the heaviest such field
+func (ix *Index) matchPhrase(snap *snapshot, phrase []string) map[int]float64 {
+	// positions[i][doc][field] are the positions of the i-th word
+	positions := make([]map[[2]int][]int, len(phrase))
+	for i, term := range phrase {
+		positions[i] = map[[2]int][]int{}
+		for _, p := range snap.postings[term] {
+			positions[i][[2]int{p.doc, p.field}] = p.positions
+		}
+	}
+
+	found := map[int]float64{}
+	for key, starts := range positions[0] {
+		for _, start := range starts {
+			if !phraseAt(positions, key, start) {
+				continue
+			}
+			if weight := ix.fields[key[1]].Weight; weight > found[key[0]] {
+				found[key[0]] = weight
+			}
+			break
+		}
+	}
+	return found
+}
+
+func phraseAt(positions []map[[2]int][]int, key [2]int, start int) bool {
+	for i := 1; i < len(positions); i++ {
+		at := positions[i][key]
+		j := sort.SearchInts(at, start+i)
+		if j == len(at) || at[j] != start+i {
+			return false
+		}
+	}
+	return true
+}
+
+// expand returns the indexed stems a query word may stand for, with the weight of each: the
+// word's own stem, and the stems of indexed words within its typo allowance
+func (snap *snapshot) expand(word string) map[string]float64 {
+	terms := map[string]float64{}
+	if stemmed := stem(word); len(snap.postings[stemmed]) > 0 {
+		terms[stemmed] = 1
+	}
+
+	allowed := typoAllowance(word)
+	if allowed == 0 {
+		return terms
+	}
+	for candidate, stemmed := range snap.words {
+		if _, ok := terms[stemmed]; ok || !sameFirstRune(word, candidate) {
+			continue
+		}
+		if withinDistance(word, candidate, allowed) {
+			terms[stemmed] = fuzzyWeight
+		}
+	}
+	return terms
+}
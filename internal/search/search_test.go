@@ -0,0 +1,227 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"testing"
+)
+
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+	ix := NewIndex([]Field{{Name: "name", Weight: 3}, {Name: "description", Weight: 1}})
+	ix.Rebuild([]Document{
+		{ID: "rooftop", Fields: map[string]string{
+			"name":        "Rooftop Garden",
+			"description": "An open air garden terrace for parties",
+		}},
+		{ID: "harbour", Fields: map[string]string{
+			"name":        "Harbour Hall",
+			"description": "A conference hall with a garden view",
+		}},
+		{ID: "party", Fields: map[string]string{
+			"name":        "Garden Party Loft",
+			"description": "Birthday parties and garden weddings in the city",
+		}},
+		{ID: "city", Fields: map[string]string{
+			"name":        "City Loft",
+			"description": "A loft in the city centre with <b>bold</b> & rooftop access",
+		}},
+	})
+	if ix.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", ix.Len())
+	}
+	return ix
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := newTestIndex(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"name matches rank above description matches", "garden", []string{"rooftop", "party", "harbour"}},
+		{"shorter fields rank higher", "loft", []string{"city", "party"}},
+		{"stemming", "weddings", []string{"party"}},
+		{"stemmed forms match each other", "party", []string{"party", "rooftop"}},
+		{"every word is required", "garden hall", []string{"harbour"}},
+		{"loose stop words are not required", "the garden of", []string{"rooftop", "party", "harbour"}},
+		{"case is ignored", "HARBOUR", []string{"harbour"}},
+		{"typo in a longer word", "gardn", []string{"rooftop", "party", "harbour"}},
+		{"typo next to an exact word", "confrence hall", []string{"harbour"}},
+		{"short words need an exact match", "cty", nil},
+		{"typos keep the first letter", "karbour", nil},
+		{"quoted phrase", `"city loft"`, []string{"city"}},
+		{"phrase words must be in order", `"loft city"`, nil},
+		{"phrase with stop words", `"in the city"`, []string{"city", "party"}},
+		{"phrase and loose words", `"garden view" hall`, []string{"harbour"}},
+		{"unclosed quote runs to the end", `"open air`, []string{"rooftop"}},
+		{"no match", "ballroom", nil},
+		{"only stop words", "the and of", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(ix.Search(tt.query, 0))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSearchScores(t *testing.T) {
+	ix := newTestIndex(t)
+
+	exact := ix.Search("garden", 0)
+	typo := ix.Search("gardn", 0)
+	if len(exact) == 0 || len(typo) == 0 || exact[0].ID != typo[0].ID {
+		t.Fatalf("Search(garden) = %v, Search(gardn) = %v", hitIDs(exact), hitIDs(typo))
+	}
+	if typo[0].Score >= exact[0].Score {
+		t.Errorf("typo score %v should be below exact score %v", typo[0].Score, exact[0].Score)
+	}
+
+	loose := ix.Search("city loft", 0)
+	phrase := ix.Search(`"city loft"`, 0)
+	if len(loose) == 0 || len(phrase) == 0 || loose[0].ID != "city" || phrase[0].ID != "city" {
+		t.Fatalf("Search(city loft) = %v, Search(\"city loft\") = %v", hitIDs(loose), hitIDs(phrase))
+	}
+	if phrase[0].Score <= loose[0].Score {
+		t.Errorf("phrase score %v should be above loose score %v", phrase[0].Score, loose[0].Score)
+	}
+
+	if got := ix.Search("garden", 2); len(got) != 2 {
+		t.Errorf("Search with limit 2 returned %d hits", len(got))
+	}
+}
+
+func TestIndexSearchHighlights(t *testing.T) {
+	ix := newTestIndex(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  map[string]string
+	}{
+		{"matched fields only", "harbour", map[string]string{"name": "<mark>Harbour</mark> Hall"}},
+		{"stemmed and fuzzy matches", "gardn parties", map[string]string{
+			"name":        "<mark>Garden</mark> <mark>Party</mark> Loft",
+			"description": "Birthday <mark>parties</mark> and <mark>garden</mark> weddings in the city",
+		}},
+		{"text is escaped", "bold", map[string]string{
+			"description": "A loft in the city centre with &lt;b&gt;<mark>bold</mark>&lt;/b&gt; &amp; rooftop access",
+		}},
+		{"stop words of a phrase are not marked", `"in the city"`, map[string]string{
+			"name":        "<mark>City</mark> Loft",
+			"description": "A loft in the <mark>city</mark> centre with &lt;b&gt;bold&lt;/b&gt; &amp; rooftop access",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := ix.Search(tt.query, 1)
+			if len(hits) != 1 {
+				t.Fatalf("Search(%q) returned %d hits", tt.query, len(hits))
+			}
+			got := hits[0].Highlights
+			if len(got) != len(tt.want) {
+				t.Fatalf("Highlights = %q, want %q", got, tt.want)
+			}
+			for field, want := range tt.want {
+				if got[field] != want {
+					t.Errorf("Highlights[%s] = %q, want %q", field, got[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("filler ", 40) + "the garden <terrace> " + strings.Repeat("filler ", 40)
+	got, ok := snippet(long, map[string]bool{"garden": true})
+	if !ok {
+		t.Fatal("snippet found no match")
+	}
+	if !strings.HasPrefix(got, "…filler filler") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet = %q, want a window marked with … on both sides", got)
+	}
+	if !strings.Contains(got, "the <mark>garden</mark> &lt;terrace&gt;") {
+		t.Errorf("snippet = %q, want the match marked and the text escaped", got)
+	}
+	if text := html.UnescapeString(strings.NewReplacer("<mark>", "", "</mark>", "", "…", "").Replace(got)); len(text) > snippetBytes {
+		t.Errorf("snippet text is %d bytes, want at most %d", len(text), snippetBytes)
+	}
+
+	if _, ok := snippet("nothing here", map[string]bool{"garden": true}); ok {
+		t.Error("snippet without a match should report false")
+	}
+}
+
+func TestWithinDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  bool
+	}{
+		{"garden", "garden", 0, true},
+		{"gardn", "garden", 1, true},
+		{"gardne", "garden", 1, false},
+		{"gardne", "garden", 2, true},
+		{"conference", "confrence", 1, true},
+		{"conference", "conferance", 1, true},
+		{"hall", "hallway", 2, false},
+		{"café", "cafe", 1, true},
+	}
+	for _, tt := range tests {
+		if got := withinDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("withinDistance(%q, %q, %d) = %v, want %v", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	s := NewSuggester()
+	s.Rebuild([]Suggestion{
+		{Kind: "venue", Text: "Rooftop Garden", Ref: "rooftop", Weight: 5},
+		{Kind: "venue", Text: "Garden Party Loft", Ref: "party", Weight: 9},
+		{Kind: "venue", Text: "Garage Studio", Ref: "garage", Weight: 9},
+		{Kind: "region", Text: "Greater Accra", Weight: 3},
+		{Kind: "venue", Text: "Garden Garden", Ref: "twice", Weight: 1},
+	})
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"heaviest first, shorter on ties", "gar", 0, []string{"Garage Studio", "Garden Party Loft", "Rooftop Garden", "Garden Garden"}},
+		{"limit", "gar", 2, []string{"Garage Studio", "Garden Party Loft"}},
+		{"any word of the suggestion", "accra", 0, []string{"Greater Accra"}},
+		{"words must follow each other", "garden p", 0, []string{"Garden Party Loft"}},
+		{"words out of order", "party garden", 0, nil},
+		{"case and punctuation are ignored", "  ROOFTOP-gard ", 0, []string{"Rooftop Garden"}},
+		{"no match", "ballroom", 0, nil},
+		{"empty prefix", " ", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := s.Suggest(tt.prefix, tt.limit)
+			got := make([]string, len(found))
+			for i, suggestion := range found {
+				got[i] = suggestion.Text
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// snippetBytes bounds the length of a highlight snippet, before markup
+	snippetBytes = 160
+	// snippetLead is how many words of context precede the first match
+	snippetLead = 5
+)
+
+// highlight builds a snippet of every field of doc containing one of the matched stems
+func (ix *Index) highlight(doc indexedDoc, matched map[string]bool) map[string]string {
+	highlights := map[string]string{}
+	for f, field := range ix.fields {
+		if snippet, ok := snippet(doc.text[f], matched); ok {
+			highlights[field.Name] = snippet
+		}
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// snippet cuts a window of text starting shortly before the first match, escapes it and marks
+// the matched words. Windows that do not reach the start or end of text are marked with "…".
+func snippet(text string, matched map[string]bool) (string, bool) {
+	tokens := tokenize(text)
+	first := -1
+	hit := make([]bool, len(tokens))
+	for i, t := range tokens {
+		if matched[stem(t.word)] {
+			hit[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	startTok := 0
+	if len(text) > snippetBytes {
+		startTok = max(first-snippetLead, 0)
+	}
+	start := 0
+	if startTok > 0 {
+		start = tokens[startTok].start
+	}
+	end := len(text)
+	if end-start > snippetBytes {
+		end = tokens[startTok].end
+		for i := startTok; i < len(tokens) && tokens[i].end-start <= snippetBytes; i++ {
+			end = tokens[i].end
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	at := start
+	for i := startTok; i < len(tokens) && tokens[i].end <= end; i++ {
+		if !hit[i] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[at:tokens[i].start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[tokens[i].start:tokens[i].end]))
+		b.WriteString("</mark>")
+		at = tokens[i].end
+	}
+	b.WriteString(html.EscapeString(text[at:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Suggestion is a completion offered for what a user has typed so far
+type Suggestion struct {
+	// Kind tells what the text names, e.g. a venue or a region
+	Kind string
+	Text string
+	// Ref identifies what the suggestion leads to, where there is one thing
+	Ref string
+	// Weight ranks suggestions, higher first
+	Weight float64
+}
+
+// suggestKey is the normalised text of a suggestion from one of its words onwards, so
+// "garden" completes to "Rooftop Garden" as well
+type suggestKey struct {
+	text  string
+	entry int
+}
+
+// Suggester completes prefixes from a sorted list of keys. Like Index, it is safe for
+// concurrent use and rebuilt wholesale.
+type Suggester struct {
+	mu      sync.RWMutex
+	entries []Suggestion
+	keys    []suggestKey
+}
+
+func NewSuggester() *Suggester {
+	return &Suggester{}
+}
+
+// Rebuild replaces the suggestions on offer
+func (s *Suggester) Rebuild(entries []Suggestion) {
+	var keys []suggestKey
+	for i, entry := range entries {
+		words := normalize(entry.Text)
+		for w := range words {
+			keys = append(keys, suggestKey{text: strings.Join(words[w:], " "), entry: i})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].text < keys[j].text })
+
+	s.mu.Lock()
+	s.entries, s.keys = entries, keys
+	s.mu.Unlock()
+}
+
+// Suggest returns up to limit suggestions with a word starting with prefix, the heaviest first.
+// Words of a prefix with several must follow each other in the suggestion.
+func (s *Suggester) Suggest(prefix string, limit int) []Suggestion {
+	words := normalize(prefix)
+	if len(words) == 0 {
+		return nil
+	}
+	key := strings.Join(words, " ")
+
+	s.mu.RLock()
+	entries, keys := s.entries, s.keys
+	s.mu.RUnlock()
+
+	seen := map[int]bool{}
+	var found []Suggestion
+	for i := sort.Search(len(keys), func(i int) bool { return keys[i].text >= key }); i < len(keys); i++ {
+		if !strings.HasPrefix(keys[i].text, key) {
+			break
+		}
+		if !seen[keys[i].entry] {
+			seen[keys[i].entry] = true
+			found = append(found, entries[keys[i].entry])
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Weight != found[j].Weight {
+			return found[i].Weight > found[j].Weight
+		}
+		if len(found[i].Text) != len(found[j].Text) {
+			return len(found[i].Text) < len(found[j].Text)
+		}
+		return found[i].Text < found[j].Text
+	})
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// normalize lowercases text into its words, dropping punctuation
+func normalize(text string) []string {
+	tokens := tokenize(text)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return words
+}
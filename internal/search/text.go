@@ -0,0 +1,143 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+)
+
+// maxQueryWords bounds the work a single query can cause
+const maxQueryWords = 16
+
+// Stop words are indexed, so quoted phrases containing them still match, but are not required
+// when they appear loose in a query
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+type token struct {
+	word       string
+	start, end int
+}
+
+// tokenize splits text into lowercased runs of letters and digits with their byte offsets
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// stem reduces a lowercased word to its English stem so "parties" finds "party"
+func stem(word string) string {
+	return english.Stem(word, false)
+}
+
+type query struct {
+	// terms are the loose words, all of which must match
+	terms []string
+	// phrases are the stems of each quoted phrase
+	phrases [][]string
+}
+
+// parseQuery splits a query into loose words and "quoted phrases". Words of a phrase also
+// count as loose words, so they are scored like the rest; an unclosed quote runs to the end.
+func parseQuery(text string) query {
+	var q query
+	seen := map[string]bool{}
+	addTerm := func(word string) {
+		if stopWords[word] || seen[word] || len(q.terms) >= maxQueryWords {
+			return
+		}
+		seen[word] = true
+		q.terms = append(q.terms, word)
+	}
+
+	for i, part := range strings.Split(text, `"`) {
+		tokens := tokenize(part)
+		// Odd parts sit between quotes
+		if i%2 == 1 && len(tokens) > 1 {
+			phrase := make([]string, 0, len(tokens))
+			for _, t := range tokens[:min(len(tokens), maxQueryWords)] {
+				phrase = append(phrase, stem(t.word))
+			}
+			q.phrases = append(q.phrases, phrase)
+		}
+		for _, t := range tokens {
+			addTerm(t.word)
+		}
+	}
+	return q
+}
+
+// typoAllowance is how many edits a query word may be away from an indexed word. Short words
+// must match exactly, since one edit already turns them into many other words.
+func typoAllowance(word string) int {
+	switch n := utf8.RuneCountInString(word); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sameFirstRune keeps typo correction to words that start alike, as first letters are rarely
+// mistyped and this keeps corrections plausible
+func sameFirstRune(a, b string) bool {
+	ra, _ := utf8.DecodeRuneInString(a)
+	rb, _ := utf8.DecodeRuneInString(b)
+	return ra == rb
+}
+
+// withinDistance reports whether a and b are at most limit edits apart, counting insertions,
+// deletions and substitutions
+func withinDistance(a, b string, limit int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return false
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			best = min(best, curr[j])
+		}
+		// No later row can get below the best of this one
+		if best > limit {
+			return false
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)] <= limit
+}
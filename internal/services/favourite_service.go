@@ -52,6 +52,40 @@ func (fs *FavouriteService) GetFavouritesByUserID(ctx context.Context, userId uu
 	return fs.favouritesRepo.GetFavouritesByUserID(ctx, userId)
 }
 
+// ListFavouriteItems returns a page of a user's favourite items, most recently added first
+func (fs *FavouriteService) ListFavouriteItems(ctx context.Context, userId uuid.UUID, page models.PageRequest) ([]models.FavouriteItem, models.PageInfo, error) {
+	if userId == uuid.Nil {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid user ID")
+	}
+	if page.Limit <= 0 {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid limit")
+	}
+
+	scope := "favourites:" + userId.String()
+	cursor, err := models.DecodeCursor(page.Cursor, scope)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	items, err := fs.favouritesRepo.ListFavouriteItems(ctx, userId, cursor, page.Limit+1)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	items, hasMore := models.TrimPage(items, page.Limit, cursor != nil && cursor.Backward)
+
+	var info models.PageInfo
+	if len(items) > 0 {
+		first, last := models.FavouriteCursorKey(items[0]), models.FavouriteCursorKey(items[len(items)-1])
+		info = models.KeysetPage(scope, cursor, first, last, hasMore)
+	}
+	if page.WithTotal {
+		if info.Total, err = fs.favouritesRepo.CountFavouriteItems(ctx, userId); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+	}
+	return items, info, nil
+}
+
 // func (fs *FavouriteService) GetFavById(ctx context.Context, id primitive.ObjectID) (*models.Favourite, error) {
 // 	if id.IsZero() {
 // 		return nil, fmt.Errorf("invalid favourite ID")
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// recentGuestReviews is how many revealed reviews are included in a guest's reputation summary
+	recentGuestReviews = 5
+	// revealBatch bounds how many expired blind reviews one run reveals
+	revealBatch = 500
+)
+
+var (
+	ErrNotBookingHost           = errors.New("only the venue's host can review this guest")
+	ErrGuestReviewAlreadyExists = models.ErrGuestReviewAlreadyExists
+)
+
+// blindUntil returns when a new review for booking becomes visible. Reviews are published
+// immediately once the other side has reviewed, otherwise when the review window closes.
+func (rs *ReviewService) blindUntil(booking *models.Bookings, counterpartExists bool, now time.Time) time.Time {
+	if counterpartExists || rs.settings.Window <= 0 {
+		return now
+	}
+	return booking.EndTime.Add(rs.settings.Window)
+}
+
+// CreateGuestReview records the venue host's rating of the guest on a completed booking
+func (rs *ReviewService) CreateGuestReview(ctx context.Context, hostId uuid.UUID, review *models.GuestReview, accessToken string) (*models.GuestReview, error) {
+	if hostId == uuid.Nil {
+		return nil, fmt.Errorf("invalid host ID")
+	}
+	if review.BookingID == uuid.Nil {
+		return nil, ErrBookingRequired
+	}
+
+	booking, err := rs.bookingsRepo.GetBookingByID(ctx, review.BookingID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify booking: %w", err)
+	}
+
+	venue, err := rs.venuesRepo.ListVenueByID(ctx, booking.VenueId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load venue for booking: %w", err)
+	}
+	if venue.HostId != hostId {
+		return nil, ErrNotBookingHost
+	}
+
+	now := time.Now()
+	if err := rs.checkBookingReviewable(booking, now); err != nil {
+		return nil, err
+	}
+
+	existing, err := rs.guestReviewsRepo.GetGuestReviewByBooking(ctx, review.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrGuestReviewAlreadyExists
+	}
+
+	// Double-blind: stay hidden until the guest reviews the venue or the window closes
+	counterpart, err := rs.reviewsRepo.GetReviewByBooking(ctx, review.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Sanitize()
+	// Guest reviews have no moderation queue, so held comments are refused
+	moderated := rs.moderator.Moderate(ctx, moderation.SurfaceGuestReview, review.Comment)
+	if err := moderated.Err(); err != nil {
+		return nil, err
+	}
+	review.Comment = moderated.Text
+	review.ID = primitive.NilObjectID
+	review.VenueID = booking.VenueId
+	review.HostID = hostId
+	review.GuestID = booking.UserId
+	review.BlindUntil = rs.blindUntil(booking, counterpart != nil, now)
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	created, err := rs.guestReviewsRepo.CreateGuestReview(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if counterpart != nil {
+		before, after, err := rs.reviewsRepo.RevealReview(ctx, review.BookingID, now)
+		if err != nil {
+			fmt.Printf("Failed to reveal venue review for booking %s: %v\n", review.BookingID, err)
+		} else if after != nil {
+			// The guest's rating only reaches the venue aggregate now that both sides have reviewed
+			rs.refreshVenueRating(ctx, after.VenueID, before, after)
+		}
+	}
+
+	return created, nil
+}
+
+// RevealExpiredReviews reveals the venue reviews whose blind period has run out and adds
+// their ratings to the venue aggregates. It returns how many were revealed.
+func (rs *ReviewService) RevealExpiredReviews(ctx context.Context) (int, error) {
+	now := time.Now()
+	revealed := 0
+	for revealed < revealBatch {
+		before, after, err := rs.reviewsRepo.RevealExpiredReview(ctx, now)
+		if err != nil {
+			return revealed, err
+		}
+		if after == nil {
+			break
+		}
+		rs.refreshVenueRating(ctx, after.VenueID, before, after)
+		revealed++
+	}
+	return revealed, nil
+}
+
+// WatchBlindReviews runs RevealExpiredReviews every interval until ctx is done
+func (rs *ReviewService) WatchBlindReviews(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			revealed, err := rs.RevealExpiredReviews(ctx)
+			if err != nil {
+				fmt.Printf("[reviews] failed to reveal expired blind reviews: %v\n", err)
+			}
+			if revealed > 0 {
+				fmt.Printf("[reviews] revealed %d blind reviews\n", revealed)
+			}
+		}
+	}
+}
+
+// GetGuestReviewsAbout returns the reviews hosts have written about a guest that are no longer blind
+func (rs *ReviewService) GetGuestReviewsAbout(ctx context.Context, guestId uuid.UUID) ([]*models.GuestReview, error) {
+	if guestId == uuid.Nil {
+		return nil, fmt.Errorf("invalid guest ID")
+	}
+
+	return rs.guestReviewsRepo.GetGuestReviewsByGuest(ctx, guestId, true)
+}
+
+// GetGuestReviewsWritten returns every guest review a host has written, including blind ones
+func (rs *ReviewService) GetGuestReviewsWritten(ctx context.Context, hostId uuid.UUID) ([]*models.GuestReview, error) {
+	if hostId == uuid.Nil {
+		return nil, fmt.Errorf("invalid host ID")
+	}
+
+	return rs.guestReviewsRepo.GetGuestReviewsByHost(ctx, hostId)
+}
+
+// GetGuestReputation summarises a guest's revealed reviews for hosts deciding on a booking
+func (rs *ReviewService) GetGuestReputation(ctx context.Context, guestId uuid.UUID) (*models.GuestReputation, error) {
+	if guestId == uuid.Nil {
+		return nil, fmt.Errorf("invalid guest ID")
+	}
+
+	return rs.guestReviewsRepo.GetGuestReputation(ctx, guestId, recentGuestReviews)
+}
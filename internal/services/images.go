@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/joshua-takyi/ww/internal/imaging"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/storage"
+)
+
+// storeImage cleans an uploaded image, stores it under folder and stores one resized
+// rendition per spec next to it as "<publicID>-<name>". Nothing is left in storage on failure.
+func storeImage(ctx context.Context, media storage.MediaStore, r io.Reader, folder string, specs []imaging.Spec) (*storage.Asset, models.ImageVariants, error) {
+	processed, err := imaging.Process(r, specs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	asset, err := media.Upload(ctx, bytes.NewReader(processed.Original.Data), folder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variants := make(models.ImageVariants, len(processed.Variants))
+	for _, rendition := range processed.Variants {
+		stored, err := media.Put(ctx, bytes.NewReader(rendition.Data), variantPublicID(asset.PublicID, rendition.Name))
+		if err != nil {
+			if delErr := media.Delete(ctx, imageAssetIDs(asset.PublicID, variants)...); delErr != nil {
+				fmt.Printf("Failed to clean up image variants: %v\n", delErr)
+			}
+			return nil, nil, err
+		}
+
+		variant := models.ImageVariant{
+			URL:    stored.URL,
+			Width:  rendition.Width,
+			Height: rendition.Height,
+		}
+		if webp, err := media.VariantURL(stored.PublicID, storage.Transform{Format: "webp"}); err == nil {
+			variant.WebPURL = webp
+		}
+		variants[rendition.Name] = variant
+	}
+
+	return asset, variants, nil
+}
+
+func variantPublicID(publicID, name string) string {
+	return publicID + "-" + name
+}
+
+// imageAssetIDs lists the public IDs of an image and its recorded variants
+func imageAssetIDs(publicID string, variants models.ImageVariants) []string {
+	if publicID == "" {
+		return nil
+	}
+	ids := []string{publicID}
+	for name := range variants {
+		ids = append(ids, variantPublicID(publicID, name))
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService struct {
+	notificationsRepo models.NotificationsRepo
+}
+
+func NewNotificationService(notificationsRepo models.NotificationsRepo) *NotificationService {
+	return &NotificationService{
+		notificationsRepo: notificationsRepo,
+	}
+}
+
+func (ns *NotificationService) EnsureIndexes(ctx context.Context) error {
+	return ns.notificationsRepo.EnsureNotificationIndexes(ctx)
+}
+
+// Notify sends a notification and reports whether it was sent. Notifications reusing a dedupe
+// key are dropped, so jobs can safely retry.
+func (ns *NotificationService) Notify(ctx context.Context, notification *models.Notification) (bool, error) {
+	if notification.UserID == uuid.Nil {
+		return false, fmt.Errorf("invalid user ID")
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+	return ns.notificationsRepo.CreateNotification(ctx, notification)
+}
+
+// ListNotifications returns a page of a user's notifications, newest first
+func (ns *NotificationService) ListNotifications(ctx context.Context, userId uuid.UUID, page models.PageRequest) ([]*models.Notification, models.PageInfo, error) {
+	if userId == uuid.Nil {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid user ID")
+	}
+	if page.Limit <= 0 {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid limit")
+	}
+
+	scope := "notifications:" + userId.String()
+	cursor, err := models.DecodeCursor(page.Cursor, scope)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	notifications, err := ns.notificationsRepo.ListNotifications(ctx, userId, cursor, page.Limit+1)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	notifications, hasMore := models.TrimPage(notifications, page.Limit, cursor != nil && cursor.Backward)
+
+	var info models.PageInfo
+	if len(notifications) > 0 {
+		first, last := models.NotificationCursorKey(notifications[0]), models.NotificationCursorKey(notifications[len(notifications)-1])
+		info = models.KeysetPage(scope, cursor, first, last, hasMore)
+	}
+	if page.WithTotal {
+		if info.Total, err = ns.notificationsRepo.CountNotifications(ctx, userId, false); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+	}
+	return notifications, info, nil
+}
+
+// CountUnread returns how many of a user's notifications are unread
+func (ns *NotificationService) CountUnread(ctx context.Context, userId uuid.UUID) (int, error) {
+	if userId == uuid.Nil {
+		return 0, fmt.Errorf("invalid user ID")
+	}
+	return ns.notificationsRepo.CountNotifications(ctx, userId, true)
+}
+
+func (ns *NotificationService) MarkRead(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error {
+	if userId == uuid.Nil || id.IsZero() {
+		return fmt.Errorf("invalid user ID or notification ID")
+	}
+	return ns.notificationsRepo.MarkNotificationRead(ctx, userId, id)
+}
@@ -0,0 +1,617 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/imaging"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
+	"github.com/joshua-takyi/ww/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrBookingRequired      = errors.New("a booking ID is required to review a venue")
+	ErrBookingNotOwned      = errors.New("booking does not belong to the reviewer")
+	ErrBookingVenueMismatch = errors.New("booking is not for this venue")
+	ErrBookingNotCompleted  = errors.New("booking has not been completed yet")
+	ErrReviewWindowClosed   = errors.New("the review window for this booking has closed")
+	ErrReviewAlreadyExists  = models.ErrReviewAlreadyExists
+	ErrRejectionReason      = errors.New("a reason is required when rejecting a review")
+	ErrInvalidReviewStatus  = errors.New("invalid review status")
+	ErrCannotReportOwn      = errors.New("you cannot report your own review")
+	ErrNotVenueHost         = errors.New("only the venue's host can respond to its reviews")
+	ErrReviewNotPublic      = errors.New("only public reviews can receive a host response")
+	ErrResponseRequired     = errors.New("response body cannot be empty")
+	ErrResponseEditClosed   = errors.New("the edit window for this response has closed")
+	ErrNoPhotos             = errors.New("at least one photo is required")
+	ErrTooManyPhotos        = models.ErrTooManyReviewPhotos
+	ErrPhotoTooLarge        = errors.New("photo exceeds the maximum allowed size")
+	ErrUnsupportedPhoto     = errors.New("photo must be a JPEG, PNG or WebP image")
+	ErrPhotoNotFound        = errors.New("photo not found on this review")
+)
+
+// ReviewSettings holds the configurable review policies
+type ReviewSettings struct {
+	// Window is how long after a booking ends the guest may review it; zero disables the limit
+	Window time.Duration
+	// ReportThreshold is the number of user reports that automatically hides a review
+	ReportThreshold int
+	// ResponseEditWindow is how long a host may edit or remove their response after first posting it
+	ResponseEditWindow time.Duration
+	// MaxPhotos is the maximum number of photos attached to a single review
+	MaxPhotos int
+	// MaxPhotoBytes is the maximum size of a single uploaded photo
+	MaxPhotoBytes int64
+}
+
+type ReviewService struct {
+	reviewsRepo      models.ReviewsRepo
+	guestReviewsRepo models.GuestReviewsRepo
+	bookingsRepo     models.BookingsRepo
+	ratingsRepo      models.RatingAggregatesRepo
+	venuesRepo       models.VenuesRepo
+	moderator        *moderation.Moderator
+	media            storage.MediaStore
+	settings         ReviewSettings
+}
+
+func NewReviewService(reviewsRepo models.ReviewsRepo, guestReviewsRepo models.GuestReviewsRepo, bookingsRepo models.BookingsRepo, ratingsRepo models.RatingAggregatesRepo, venuesRepo models.VenuesRepo, moderator *moderation.Moderator, media storage.MediaStore, settings ReviewSettings) *ReviewService {
+	return &ReviewService{
+		reviewsRepo:      reviewsRepo,
+		guestReviewsRepo: guestReviewsRepo,
+		bookingsRepo:     bookingsRepo,
+		ratingsRepo:      ratingsRepo,
+		venuesRepo:       venuesRepo,
+		moderator:        moderator,
+		media:            media,
+		settings:         settings,
+	}
+}
+
+func (rs *ReviewService) EnsureIndexes(ctx context.Context) error {
+	if err := rs.reviewsRepo.EnsureReviewIndexes(ctx); err != nil {
+		return err
+	}
+	return rs.guestReviewsRepo.EnsureGuestReviewIndexes(ctx)
+}
+
+// verifyBookingForReview checks that a booking entitles userId to review venueId at time now.
+func (rs *ReviewService) verifyBookingForReview(booking *models.Bookings, userId, venueId uuid.UUID, now time.Time) error {
+	if booking.UserId != userId {
+		return ErrBookingNotOwned
+	}
+	if booking.VenueId != venueId {
+		return ErrBookingVenueMismatch
+	}
+	return rs.checkBookingReviewable(booking, now)
+}
+
+// checkBookingReviewable checks that a booking has ended and is still within the review window.
+// The same window applies to guests reviewing venues and hosts reviewing guests.
+func (rs *ReviewService) checkBookingReviewable(booking *models.Bookings, now time.Time) error {
+	if booking.Status == models.BookingStatusCanceled || booking.Status == models.BookingStatusPending {
+		return ErrBookingNotCompleted
+	}
+	if booking.EndTime.IsZero() || now.Before(booking.EndTime) {
+		return ErrBookingNotCompleted
+	}
+	if rs.settings.Window > 0 && now.After(booking.EndTime.Add(rs.settings.Window)) {
+		return ErrReviewWindowClosed
+	}
+	return nil
+}
+
+func (rs *ReviewService) CreateReview(ctx context.Context, userId uuid.UUID, venueId uuid.UUID, review *models.VenueReview, accessToken string) (*models.VenueReview, error) {
+	if userId == uuid.Nil || venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID or venue ID")
+	}
+	if review.BookingID == uuid.Nil {
+		return nil, ErrBookingRequired
+	}
+
+	booking, err := rs.bookingsRepo.GetBookingByID(ctx, review.BookingID, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify booking: %w", err)
+	}
+
+	now := time.Now()
+	if err := rs.verifyBookingForReview(booking, userId, venueId, now); err != nil {
+		return nil, err
+	}
+
+	existing, err := rs.reviewsRepo.GetReviewByBooking(ctx, review.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrReviewAlreadyExists
+	}
+
+	review.Sanitize()
+	review.ID = primitive.NilObjectID
+	review.VerifiedBooking = true
+	if review.EventDate.IsZero() {
+		review.EventDate = booking.StartTime
+	}
+	// Every new review waits in the moderation queue before it is public
+	review.Status = models.ReviewStatusPending
+	// Double-blind: stay hidden until the host reviews the guest or the window closes
+	counterpart, err := rs.guestReviewsRepo.GetGuestReviewByBooking(ctx, review.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	blindUntil := rs.blindUntil(booking, counterpart != nil, now)
+	review.BlindUntil = &blindUntil
+	review.Revealed = !blindUntil.After(now)
+	review.ModerationReason = ""
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+	review.ReportCount = 0
+	// Photos can only be attached through AddReviewPhotos
+	review.Images = nil
+	review.ImagePublicIDs = nil
+	rs.moderateReview(ctx, review)
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	created, err := rs.reviewsRepo.CreateReview(ctx, userId, venueId, review)
+	if err != nil {
+		return nil, err
+	}
+	if counterpart != nil {
+		if err := rs.guestReviewsRepo.RevealGuestReview(ctx, review.BookingID, now); err != nil {
+			fmt.Printf("Failed to reveal guest review for booking %s: %v\n", review.BookingID, err)
+		}
+	}
+
+	rs.refreshVenueRating(ctx, venueId, nil, created)
+	return created, nil
+}
+
+func (rs *ReviewService) GetReviewsByVenue(ctx context.Context, venueId uuid.UUID) ([]*models.VenueReview, error) {
+	if venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid venue ID")
+	}
+
+	// Only approved reviews past their double-blind period are public
+	return rs.reviewsRepo.GetReviewsByVenue(ctx, venueId, models.ReviewStatusApproved, true)
+}
+
+func (rs *ReviewService) GetReviewsByUser(ctx context.Context, userId uuid.UUID) ([]*models.VenueReview, error) {
+	if userId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	return rs.reviewsRepo.GetReviewsByUser(ctx, userId)
+}
+
+func (rs *ReviewService) UpdateReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, updated *models.VenueReview) (*models.VenueReview, error) {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or review ID")
+	}
+
+	updated.Sanitize()
+	// Edited content has to be moderated again
+	updated.Status = models.ReviewStatusPending
+	updated.ModerationReason = ""
+	rs.moderateReview(ctx, updated)
+
+	// The delta comes from the document the update replaced, so concurrent changes of the
+	// same review are each counted exactly once
+	before, result, err := rs.reviewsRepo.UpdateReview(ctx, userId, reviewId, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.refreshVenueRating(ctx, result.VenueID, before, result)
+	return result, nil
+}
+
+func (rs *ReviewService) DeleteReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID) error {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return fmt.Errorf("invalid user ID or review ID")
+	}
+
+	deleted, err := rs.reviewsRepo.DeleteReview(ctx, userId, reviewId)
+	if err != nil {
+		return err
+	}
+
+	rs.deleteReviewAssets(ctx, deleted.ImagePublicIDs)
+	rs.refreshVenueRating(ctx, deleted.VenueID, deleted, nil)
+	return nil
+}
+
+// moderateReview masks offending terms in a review and sends it to the flagged queue
+// when the automatic checks will not let it through as is
+func (rs *ReviewService) moderateReview(ctx context.Context, review *models.VenueReview) {
+	title := rs.moderator.Moderate(ctx, moderation.SurfaceReview, review.Title)
+	comment := rs.moderator.Moderate(ctx, moderation.SurfaceReview, review.Comment)
+	review.Title = title.Text
+	review.Comment = comment.Text
+
+	held := comment
+	if !held.Held() {
+		held = title
+	}
+	if held.Held() {
+		review.Status = models.ReviewStatusFlagged
+		review.ModerationReason = "held automatically: " + strings.Join(held.Reasons, ", ")
+	}
+}
+
+// GetModerationQueue lists reviews awaiting a moderation decision in the given status
+func (rs *ReviewService) GetModerationQueue(ctx context.Context, status string, offset, limit int) ([]*models.VenueReview, int, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, 0, fmt.Errorf("invalid offset or limit")
+	}
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+	if !models.IsValidReviewStatus(status) {
+		return nil, 0, ErrInvalidReviewStatus
+	}
+
+	return rs.reviewsRepo.GetReviewsByStatus(ctx, status, offset, limit)
+}
+
+// ModerateReview approves or rejects a review on behalf of an admin
+func (rs *ReviewService) ModerateReview(ctx context.Context, moderatorId uuid.UUID, reviewId primitive.ObjectID, approve bool, reason string) (*models.VenueReview, error) {
+	if moderatorId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid moderator ID or review ID")
+	}
+
+	status := models.ReviewStatusApproved
+	if !approve {
+		status = models.ReviewStatusRejected
+		if strings.TrimSpace(reason) == "" {
+			return nil, ErrRejectionReason
+		}
+	}
+
+	before, result, err := rs.reviewsRepo.SetReviewStatus(ctx, reviewId, status, strings.TrimSpace(reason), &moderatorId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rejected reviews are never shown again, so their photos are removed from storage
+	if !approve && len(result.ImagePublicIDs) > 0 {
+		rs.deleteReviewAssets(ctx, result.ImagePublicIDs)
+		if err := rs.reviewsRepo.ClearReviewImages(ctx, reviewId); err != nil {
+			return nil, err
+		}
+		result.Images = []string{}
+		result.ImagePublicIDs = []string{}
+	}
+
+	rs.refreshVenueRating(ctx, result.VenueID, before, result)
+	return result, nil
+}
+
+// ReportReview records a user's report of a public review and hides the review once the report threshold is reached
+func (rs *ReviewService) ReportReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, reason string) (*models.VenueReview, error) {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or review ID")
+	}
+
+	existing, err := rs.reviewsRepo.GetReviewByID(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID == userId {
+		return nil, ErrCannotReportOwn
+	}
+	// Only public reviews can be reported; anything else is hidden from the reporter
+	if existing.Status != models.ReviewStatusApproved || !existing.IsRevealed(time.Now()) {
+		return nil, models.ErrReviewNotFound
+	}
+
+	result, err := rs.reviewsRepo.ReportReview(ctx, &models.ReviewReport{
+		ReviewID:  reviewId,
+		UserID:    userId,
+		Reason:    helpers.StringTrim(reason),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := rs.settings.ReportThreshold
+	if threshold > 0 && result.ReportCount >= threshold && result.Status == models.ReviewStatusApproved {
+		before, hidden, err := rs.reviewsRepo.SetReviewStatus(ctx, reviewId, models.ReviewStatusFlagged,
+			fmt.Sprintf("automatically hidden after %d reports", result.ReportCount), nil)
+		if err != nil {
+			return nil, err
+		}
+		rs.refreshVenueRating(ctx, hidden.VenueID, before, hidden)
+		result = hidden
+	}
+
+	return result, nil
+}
+
+// authorizeHostResponse loads a review and checks that userId may respond to it
+func (rs *ReviewService) authorizeHostResponse(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, isAdmin bool) (*models.VenueReview, error) {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or review ID")
+	}
+
+	review, err := rs.reviewsRepo.GetReviewByID(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		venue, err := rs.venuesRepo.ListVenueByID(ctx, review.VenueID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load venue for review: %w", err)
+		}
+		if venue.HostId != userId {
+			return nil, ErrNotVenueHost
+		}
+		// Hosts must not see a guest's review before the double-blind period ends
+		if !review.IsRevealed(time.Now()) {
+			return nil, ErrReviewNotPublic
+		}
+	}
+
+	return review, nil
+}
+
+// responseEditable reports whether an existing response may still be changed by its host
+func (rs *ReviewService) responseEditable(response *models.ReviewHostResponse, now time.Time) bool {
+	if rs.settings.ResponseEditWindow <= 0 {
+		return true
+	}
+	return now.Before(response.CreatedAt.Add(rs.settings.ResponseEditWindow))
+}
+
+// RespondToReview creates or edits the host's public reply to a review.
+// Admins bypass both the ownership check and the edit window.
+func (rs *ReviewService) RespondToReview(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, body string, isAdmin bool) (*models.VenueReview, error) {
+	review, err := rs.authorizeHostResponse(ctx, userId, reviewId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != models.ReviewStatusApproved {
+		return nil, ErrReviewNotPublic
+	}
+
+	now := time.Now()
+	response := &models.ReviewHostResponse{
+		ResponderID: userId,
+		Body:        body,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	response.Sanitize()
+	if response.Body == "" {
+		return nil, ErrResponseRequired
+	}
+	// Responses are published immediately, so anything that would be held is refused
+	moderated := rs.moderator.Moderate(ctx, moderation.SurfaceReviewResponse, response.Body)
+	if err := moderated.Err(); err != nil {
+		return nil, err
+	}
+	response.Body = moderated.Text
+
+	if existing := review.HostResponse; existing != nil {
+		if !isAdmin && !rs.responseEditable(existing, now) {
+			return nil, ErrResponseEditClosed
+		}
+		response.CreatedAt = existing.CreatedAt
+	}
+
+	return rs.reviewsRepo.SetHostResponse(ctx, reviewId, response)
+}
+
+// DeleteReviewResponse removes the host's reply, subject to the same edit window as edits
+func (rs *ReviewService) DeleteReviewResponse(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, isAdmin bool) (*models.VenueReview, error) {
+	review, err := rs.authorizeHostResponse(ctx, userId, reviewId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if review.HostResponse == nil {
+		return review, nil
+	}
+	if !isAdmin && !rs.responseEditable(review.HostResponse, time.Now()) {
+		return nil, ErrResponseEditClosed
+	}
+
+	return rs.reviewsRepo.SetHostResponse(ctx, reviewId, nil)
+}
+
+// AddReviewPhotos validates and uploads photos for a review owned by userId.
+// Files are checked for size and sniffed content type before anything is uploaded.
+func (rs *ReviewService) AddReviewPhotos(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, files []*multipart.FileHeader) (*models.VenueReview, error) {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or review ID")
+	}
+	if len(files) == 0 {
+		return nil, ErrNoPhotos
+	}
+	if rs.media == nil {
+		return nil, fmt.Errorf("image storage is not configured")
+	}
+
+	existing, err := rs.reviewsRepo.GetReviewByID(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != userId {
+		return nil, models.ErrReviewNotFound
+	}
+	if len(existing.Images)+len(files) > rs.settings.MaxPhotos {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyPhotos, rs.settings.MaxPhotos)
+	}
+
+	// Validate every file before uploading any of them
+	for _, fh := range files {
+		if fh.Size > rs.settings.MaxPhotoBytes {
+			return nil, fmt.Errorf("%w: %s", ErrPhotoTooLarge, fh.Filename)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read photo %s: %v", fh.Filename, err)
+		}
+		_, sniffErr := helpers.DetectImageType(f)
+		f.Close()
+		if sniffErr != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedPhoto, fh.Filename)
+		}
+	}
+
+	urls := make([]string, 0, len(files))
+	publicIDs := make([]string, 0, len(files))
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			rs.deleteReviewAssets(ctx, publicIDs)
+			return nil, fmt.Errorf("failed to read photo %s: %v", fh.Filename, err)
+		}
+		// Photos are only cleaned of metadata; reviews show them at a single size
+		asset, _, err := storeImage(ctx, rs.media, f, storage.ReviewFolder, nil)
+		f.Close()
+		if err != nil {
+			rs.deleteReviewAssets(ctx, publicIDs)
+			if errors.Is(err, imaging.ErrUnsupportedImage) || errors.Is(err, imaging.ErrImageTooLarge) {
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedPhoto, fh.Filename)
+			}
+			return nil, err
+		}
+		urls = append(urls, asset.URL)
+		publicIDs = append(publicIDs, asset.PublicID)
+	}
+
+	result, err := rs.reviewsRepo.AddReviewImages(ctx, userId, reviewId, urls, publicIDs, rs.settings.MaxPhotos)
+	if err != nil {
+		rs.deleteReviewAssets(ctx, publicIDs)
+		return nil, err
+	}
+
+	// New photos send the review back to moderation
+	rs.refreshVenueRating(ctx, result.VenueID, existing, result)
+	return result, nil
+}
+
+// RemoveReviewPhoto deletes the photo at index from a review owned by userId
+func (rs *ReviewService) RemoveReviewPhoto(ctx context.Context, userId uuid.UUID, reviewId primitive.ObjectID, index int) (*models.VenueReview, error) {
+	if userId == uuid.Nil || reviewId.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or review ID")
+	}
+
+	existing, err := rs.reviewsRepo.GetReviewByID(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != userId {
+		return nil, models.ErrReviewNotFound
+	}
+	if index < 0 || index >= len(existing.Images) {
+		return nil, ErrPhotoNotFound
+	}
+
+	var publicID string
+	if index < len(existing.ImagePublicIDs) {
+		publicID = existing.ImagePublicIDs[index]
+	}
+
+	result, err := rs.reviewsRepo.RemoveReviewImage(ctx, userId, reviewId, existing.Images[index], publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Photos stored before public IDs were kept have nothing to delete from storage
+	if publicID != "" {
+		rs.deleteReviewAssets(ctx, []string{publicID})
+	}
+	return result, nil
+}
+
+// deleteReviewAssets removes uploaded review photos from storage; failures are only logged
+func (rs *ReviewService) deleteReviewAssets(ctx context.Context, publicIDs []string) {
+	if len(publicIDs) == 0 || rs.media == nil {
+		return
+	}
+	if err := rs.media.Delete(ctx, publicIDs...); err != nil {
+		fmt.Printf("Failed to delete review photos: %v\n", err)
+	}
+}
+
+// refreshVenueRating moves the venue's aggregate by the difference between before and after.
+// Failures are logged rather than returned: the review itself is already persisted and
+// RebuildRatingAggregates can repair any drift.
+func (rs *ReviewService) refreshVenueRating(ctx context.Context, venueId uuid.UUID, before, after *models.VenueReview) {
+	delta := models.NewRatingDelta(before, after)
+	if delta.IsZero() {
+		return
+	}
+
+	agg, err := rs.ratingsRepo.ApplyRatingDelta(ctx, venueId, delta)
+	if err != nil {
+		fmt.Printf("Failed to update rating aggregate for venue %s: %v\n", venueId, err)
+		return
+	}
+
+	if err := rs.venuesRepo.UpdateVenueRating(ctx, venueId, agg.AverageRating, agg.ReviewCount); err != nil {
+		fmt.Printf("Failed to update venue rating columns for venue %s: %v\n", venueId, err)
+	}
+}
+
+// RebuildRatingAggregates recomputes every venue's aggregate from the stored reviews
+// and returns the number of venues that have at least one counted review.
+func (rs *ReviewService) RebuildRatingAggregates(ctx context.Context) (int, error) {
+	previous, err := rs.ratingsRepo.ListRatingAggregateVenueIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	byVenue := make(map[uuid.UUID]*models.VenueRatingAggregate)
+	err = rs.reviewsRepo.IterateReviews(ctx, func(review *models.VenueReview) error {
+		if !review.CountsTowardsRating() {
+			return nil
+		}
+		agg, ok := byVenue[review.VenueID]
+		if !ok {
+			agg = &models.VenueRatingAggregate{VenueID: review.VenueID}
+			byVenue[review.VenueID] = agg
+		}
+		agg.Add(review)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reviews: %w", err)
+	}
+
+	aggregates := make([]*models.VenueRatingAggregate, 0, len(byVenue))
+	for _, agg := range byVenue {
+		aggregates = append(aggregates, agg)
+	}
+	if err := rs.ratingsRepo.ReplaceRatingAggregates(ctx, aggregates); err != nil {
+		return 0, err
+	}
+
+	for _, agg := range aggregates {
+		if err := rs.venuesRepo.UpdateVenueRating(ctx, agg.VenueID, agg.AverageRating, agg.ReviewCount); err != nil {
+			return 0, fmt.Errorf("failed to update rating of venue %s: %w", agg.VenueID, err)
+		}
+	}
+	// Venues that used to have reviews but no longer do
+	for _, venueId := range previous {
+		if _, ok := byVenue[venueId]; !ok {
+			if err := rs.venuesRepo.UpdateVenueRating(ctx, venueId, 0, 0); err != nil {
+				return 0, fmt.Errorf("failed to update rating of venue %s: %w", venueId, err)
+			}
+		}
+	}
+
+	return len(aggregates), nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSavedSearchName = errors.New("saved search name must be 1 to 80 characters")
+	ErrTooManySavedSearches   = errors.New("saved search limit reached")
+)
+
+const (
+	// maxSavedSearches bounds how many searches one user can keep
+	maxSavedSearches = 25
+	// maxSavedSearchNameLength bounds saved search names, in characters
+	maxSavedSearchNameLength = 80
+	// savedSearchBatch is how many saved searches an alert run loads at a time
+	savedSearchBatch = 100
+	// alertActivationLimit is how many venue activations an alert run loads at a time
+	alertActivationLimit = 1000
+	// alertLookback is how far back an alert run looks for activations, so a job that was
+	// down for a long time does not announce venues that went live long ago
+	alertLookback = 24 * time.Hour
+)
+
+type SavedSearchService struct {
+	savedSearchesRepo models.SavedSearchesRepo
+	statusHistoryRepo models.VenueStatusHistoryRepo
+	venues            *VenuesService
+	notifications     *NotificationService
+}
+
+func NewSavedSearchService(savedSearchesRepo models.SavedSearchesRepo, statusHistoryRepo models.VenueStatusHistoryRepo, venues *VenuesService, notifications *NotificationService) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchesRepo: savedSearchesRepo,
+		statusHistoryRepo: statusHistoryRepo,
+		venues:            venues,
+		notifications:     notifications,
+	}
+}
+
+func (ss *SavedSearchService) EnsureIndexes(ctx context.Context) error {
+	return ss.savedSearchesRepo.EnsureSavedSearchIndexes(ctx)
+}
+
+// savedSearchName trims a saved search name and checks its length
+func savedSearchName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxSavedSearchNameLength {
+		return "", ErrInvalidSavedSearchName
+	}
+	return name, nil
+}
+
+// CreateSavedSearch saves a search for a user. params is the search as query parameters,
+// which clients send back to /venues/search to run it again.
+func (ss *SavedSearchService) CreateSavedSearch(ctx context.Context, userId uuid.UUID, name, params string, query models.VenueSearchQuery, area *geo.Area) (*models.SavedSearch, error) {
+	if userId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	name, err := savedSearchName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateVenueSearch(query, area); err != nil {
+		return nil, err
+	}
+
+	count, err := ss.savedSearchesRepo.CountSavedSearches(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxSavedSearches {
+		return nil, fmt.Errorf("%w: at most %d searches can be saved", ErrTooManySavedSearches, maxSavedSearches)
+	}
+
+	now := time.Now()
+	return ss.savedSearchesRepo.CreateSavedSearch(ctx, &models.SavedSearch{
+		UserID:    userId,
+		Name:      name,
+		Params:    params,
+		Query:     query,
+		Area:      area,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+func (ss *SavedSearchService) ListSavedSearches(ctx context.Context, userId uuid.UUID) ([]*models.SavedSearch, error) {
+	if userId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	return ss.savedSearchesRepo.ListSavedSearches(ctx, userId)
+}
+
+func (ss *SavedSearchService) RenameSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID, name string) (*models.SavedSearch, error) {
+	if userId == uuid.Nil || id.IsZero() {
+		return nil, fmt.Errorf("invalid user ID or saved search ID")
+	}
+	name, err := savedSearchName(name)
+	if err != nil {
+		return nil, err
+	}
+	return ss.savedSearchesRepo.RenameSavedSearch(ctx, userId, id, name)
+}
+
+func (ss *SavedSearchService) DeleteSavedSearch(ctx context.Context, userId uuid.UUID, id primitive.ObjectID) error {
+	if userId == uuid.Nil || id.IsZero() {
+		return fmt.Errorf("invalid user ID or saved search ID")
+	}
+	return ss.savedSearchesRepo.DeleteSavedSearch(ctx, userId, id)
+}
+
+// AlertNewMatches notifies users of venues activated since their saved searches were last
+// checked that match them, and returns how many notifications were sent. Each venue is
+// announced once per search: a search that fails keeps its mark and is checked again next
+// run, and notifications it already sent are dropped by their dedupe key.
+func (ss *SavedSearchService) AlertNewMatches(ctx context.Context) (int, error) {
+	runStart := time.Now()
+	since := runStart.Add(-alertLookback)
+
+	sent := 0
+	for {
+		changes, err := ss.statusHistoryRepo.ListVenueStatusChangesSince(ctx, models.StatusActive, since, alertActivationLimit)
+		if err != nil {
+			return sent, err
+		}
+		if len(changes) == 0 {
+			return sent, nil
+		}
+
+		// A full batch only covers activations up to its last one; searches are marked
+		// checked that far and the next batch carries on from there
+		until, full := runStart, len(changes) == alertActivationLimit
+		if full {
+			changes, until = wholeActivations(changes)
+		}
+
+		// Changes come oldest first, so a venue activated twice keeps its latest activation
+		activatedAt := make(map[uuid.UUID]time.Time, len(changes))
+		for _, change := range changes {
+			activatedAt[change.VenueID] = change.CreatedAt
+		}
+
+		n, err := ss.alertActivations(ctx, activatedAt, until)
+		sent += n
+		// Later batches would move marks past the activations that just failed
+		if err != nil || !full {
+			return sent, err
+		}
+		since = until
+	}
+}
+
+// wholeActivations drops the activations sharing the last timestamp of a full batch, since
+// the batch may have cut through them, and returns the time the rest run up to. A batch that
+// is all one timestamp is kept whole.
+func wholeActivations(changes []*models.VenueStatusChange) ([]*models.VenueStatusChange, time.Time) {
+	last := changes[len(changes)-1].CreatedAt
+	i := len(changes)
+	for i > 0 && changes[i-1].CreatedAt.Equal(last) {
+		i--
+	}
+	if i == 0 {
+		return changes, last
+	}
+	return changes[:i], changes[i-1].CreatedAt
+}
+
+// alertActivations runs every saved search against the given activations, marking each one
+// checked up to until
+func (ss *SavedSearchService) alertActivations(ctx context.Context, activatedAt map[uuid.UUID]time.Time, until time.Time) (int, error) {
+	sent := 0
+	var errs []error
+	after := primitive.NilObjectID
+	for {
+		searches, err := ss.savedSearchesRepo.ListSavedSearchBatch(ctx, after, savedSearchBatch)
+		if err != nil {
+			return sent, errors.Join(append(errs, err)...)
+		}
+
+		for _, saved := range searches {
+			n, err := ss.alertSavedSearch(ctx, saved, activatedAt, until)
+			sent += n
+			if err != nil {
+				errs = append(errs, fmt.Errorf("saved search %s: %v", saved.ID.Hex(), err))
+			}
+		}
+		if len(searches) < savedSearchBatch {
+			break
+		}
+		after = searches[len(searches)-1].ID
+	}
+	return sent, errors.Join(errs...)
+}
+
+// alertSavedSearch announces the venues activated after saved was last checked that match it,
+// then marks it checked up to until
+func (ss *SavedSearchService) alertSavedSearch(ctx context.Context, saved *models.SavedSearch, activatedAt map[uuid.UUID]time.Time, until time.Time) (int, error) {
+	// Venues that went live before the search was saved are not new to its owner
+	checkedUntil := saved.CreatedAt
+	if saved.AlertedUntil.After(checkedUntil) {
+		checkedUntil = saved.AlertedUntil
+	}
+
+	var ids []uuid.UUID
+	for id, at := range activatedAt {
+		if at.After(checkedUntil) && !at.After(until) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	matches, err := ss.venues.MatchVenues(ctx, saved.Query, saved.Area, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, venue := range matches {
+		ok, err := ss.notifications.Notify(ctx, &models.Notification{
+			UserID: saved.UserID,
+			Type:   models.NotificationSavedSearchMatch,
+			Title:  fmt.Sprintf("New venue for \"%s\"", saved.Name),
+			Body:   fmt.Sprintf("%s now matches your saved search.", venue.Name),
+			Data: map[string]string{
+				"saved_search_id": saved.ID.Hex(),
+				"venue_id":        venue.Id.String(),
+				"venue_slug":      venue.Slug,
+			},
+			DedupeKey: fmt.Sprintf("saved_search:%s:%s", saved.ID.Hex(), venue.Id),
+		})
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, ss.savedSearchesRepo.MarkSavedSearchAlerted(ctx, saved.ID, until)
+}
+
+// WatchSavedSearchAlerts runs AlertNewMatches every interval until ctx is done
+func (ss *SavedSearchService) WatchSavedSearchAlerts(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := ss.AlertNewMatches(ctx)
+			if err != nil {
+				fmt.Printf("[saved-searches] failed to alert new matches: %v\n", err)
+			}
+			if sent > 0 {
+				fmt.Printf("[saved-searches] sent %d new match notifications\n", sent)
+			}
+		}
+	}
+}
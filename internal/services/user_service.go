@@ -2,22 +2,29 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/google/uuid"
 	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/imaging"
 	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
+	"github.com/joshua-takyi/ww/internal/storage"
 )
 
 type UserService struct {
-	userRepo models.UserRepo
+	userRepo  models.UserRepo
+	moderator *moderation.Moderator
+	media     storage.MediaStore
 }
 
-func NewUserService(userRepo models.UserRepo) *UserService {
+func NewUserService(userRepo models.UserRepo, moderator *moderation.Moderator, media storage.MediaStore) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		moderator: moderator,
+		media:     media,
 	}
 }
 
@@ -38,6 +45,12 @@ func (us *UserService) CreateUser(user *models.User) (interface{}, error) {
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
+	bio, err := us.moderateBio(context.Background(), user.Bio)
+	if err != nil {
+		return nil, err
+	}
+	user.Bio = bio
+
 	return us.userRepo.CreateUser(context.Background(), user)
 }
 
@@ -80,6 +93,14 @@ func (us *UserService) UpdateUser(ctx context.Context, user map[string]interface
 	// 	return nil, err
 	// }
 
+	if bio, ok := user["bio"].(string); ok {
+		moderated, err := us.moderateBio(ctx, bio)
+		if err != nil {
+			return nil, err
+		}
+		user["bio"] = moderated
+	}
+
 	now := time.Now()
 	user["updated_at"] = now
 
@@ -91,6 +112,15 @@ func (us *UserService) UpdateUser(ctx context.Context, user map[string]interface
 	return updatedUser, nil
 }
 
+// moderateBio masks profanity in a bio; bios have no review queue, so held ones are refused
+func (us *UserService) moderateBio(ctx context.Context, bio string) (string, error) {
+	result := us.moderator.Moderate(ctx, moderation.SurfaceBio, helpers.StringTrim(bio))
+	if err := result.Err(); err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
 func (us *UserService) DeleteUser(ctx context.Context, id uuid.UUID, accessToken string) error {
 	err := us.userRepo.DeleteUser(ctx, id, accessToken)
 	if err != nil {
@@ -99,23 +129,31 @@ func (us *UserService) DeleteUser(ctx context.Context, id uuid.UUID, accessToken
 	return nil
 }
 
-func (su *UserService) UploadAvatar(ctx context.Context, userId uuid.UUID, imagePath string, accessToken string, cld *cloudinary.Cloudinary) (string, error) {
+func (su *UserService) UploadAvatar(ctx context.Context, userId uuid.UUID, imagePath string, accessToken string) (string, error) {
 	if userId == uuid.Nil {
 		return "", fmt.Errorf("no valid UUID provided")
 	}
+	if su.media == nil {
+		return "", fmt.Errorf("image storage is not configured")
+	}
 
-	// Upload image to Cloudinary
-	imageURLs, _, err := helpers.UploadImages(ctx, cld, []string{imagePath}, helpers.AvatarFolder)
+	file, err := storage.OpenSource(ctx, imagePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload image to cloudinary: %v", err)
+		return "", err
 	}
+	defer file.Close()
 
-	if len(imageURLs) == 0 {
-		return "", fmt.Errorf("no image URL returned from cloudinary")
+	// Strip metadata and store the avatar with its resized variants
+	asset, variants, err := storeImage(ctx, su.media, file, storage.AvatarFolder, imaging.AvatarSpecs)
+	if err != nil {
+		if errors.Is(err, imaging.ErrUnsupportedImage) || errors.Is(err, imaging.ErrImageTooLarge) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to upload avatar: %v", err)
 	}
 
-	// Update database with the Cloudinary URL
-	avatarURL, err := su.userRepo.UploadAvatar(ctx, userId, imageURLs[0], accessToken)
+	// Update database with the stored image URLs
+	avatarURL, err := su.userRepo.UploadAvatar(ctx, userId, asset.URL, variants, accessToken)
 	if err != nil {
 		return "", fmt.Errorf("failed to update avatar in database: %v", err)
 	}
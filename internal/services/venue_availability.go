@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// availabilityCandidateLimit bounds how many matching venues are checked against an
+// availability window. Only their ID and availability are read; pages say they are
+// truncated when more venues matched.
+const availabilityCandidateLimit = 1000
+
+// maxUTCOffset covers every timezone a venue can be in, so that one bookings query finds the
+// bookings of a date wherever the venue is
+const maxUTCOffset = 14 * time.Hour
+
+// venueBookings are the bookings blocking some window, by venue
+type venueBookings map[uuid.UUID][]models.Bookings
+
+// blockingBookings loads the bookings of the given venues that may clash with w in any timezone
+func (vs *VenuesService) blockingBookings(ctx context.Context, venueIds []uuid.UUID, w models.AvailabilityWindow) (venueBookings, error) {
+	from, to := w.Bounds(time.UTC)
+	bookings, err := vs.bookingsRepo.ListBlockingBookings(ctx, venueIds, from.Add(-maxUTCOffset), to.Add(maxUTCOffset))
+	if err != nil {
+		return nil, err
+	}
+
+	byVenue := venueBookings{}
+	for _, booking := range bookings {
+		byVenue[booking.VenueId] = append(byVenue[booking.VenueId], booking)
+	}
+	return byVenue, nil
+}
+
+// isFree reports whether venue is open during w, read in the venue's timezone, and no booking
+// overlaps it
+func (b venueBookings) isFree(venue *models.Venue, w models.AvailabilityWindow) bool {
+	if !venue.Availability.Allows(w) {
+		return false
+	}
+	start, end := w.Bounds(venue.Availability.Location())
+	for _, booking := range b[venue.Id] {
+		if booking.StartTime.Before(end) && booking.EndTime.After(start) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterAvailable keeps the venues that are free during the window of query, in order. Queries
+// without a window keep every venue.
+func (vs *VenuesService) filterAvailable(ctx context.Context, venues []*models.Venue, query models.VenueSearchQuery) ([]*models.Venue, error) {
+	if query.Window == nil || len(venues) == 0 {
+		return venues, nil
+	}
+
+	// Only venues open during the window can be booked during it
+	open := make([]*models.Venue, 0, len(venues))
+	ids := make([]uuid.UUID, 0, len(venues))
+	for _, venue := range venues {
+		if venue.Availability.Allows(*query.Window) {
+			open = append(open, venue)
+			ids = append(ids, venue.Id)
+		}
+	}
+	if len(open) == 0 {
+		return open, nil
+	}
+	bookings, err := vs.blockingBookings(ctx, ids, *query.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make([]*models.Venue, 0, len(open))
+	for _, venue := range open {
+		if bookings.isFree(venue, *query.Window) {
+			free = append(free, venue)
+		}
+	}
+	return free, nil
+}
+
+// availableVenueIDs returns the venues matching query that are free during its window, in the
+// column order of its sort. truncated is set when more than availabilityCandidateLimit venues
+// matched, so only the first of them were checked.
+func (vs *VenuesService) availableVenueIDs(ctx context.Context, query models.VenueSearchQuery) (ids []uuid.UUID, truncated bool, err error) {
+	// One extra venue tells whether some matches are left unchecked
+	venues, err := vs.venuesRepo.ListVenueAvailability(ctx, query, availabilityCandidateLimit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(venues) > availabilityCandidateLimit {
+		venues, truncated = venues[:availabilityCandidateLimit], true
+	}
+	free, err := vs.filterAvailable(ctx, venues, query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ids = make([]uuid.UUID, len(free))
+	for i, venue := range free {
+		ids[i] = venue.Id
+	}
+	return ids, truncated, nil
+}
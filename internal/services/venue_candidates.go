@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// venueCandidates are the venues an index outside the database found for a search: the
+// locator for an area, the search index for text, or both
+type venueCandidates struct {
+	// ids are the most relevant first for text searches, otherwise nearest first
+	ids        []uuid.UUID
+	distances  map[uuid.UUID]*float64
+	highlights map[uuid.UUID]map[string]string
+}
+
+// needsCandidates reports whether a search has to go through findCandidates
+func needsCandidates(query models.VenueSearchQuery, area *geo.Area) bool {
+	return area != nil || query.Text != ""
+}
+
+// findCandidates runs the area and text parts of a search. Venues must satisfy both.
+func (vs *VenuesService) findCandidates(ctx context.Context, query models.VenueSearchQuery, area *geo.Area) (*venueCandidates, error) {
+	found := &venueCandidates{}
+
+	var inArea map[uuid.UUID]bool
+	if area != nil {
+		nearby, err := vs.locateInArea(ctx, *area)
+		if err != nil {
+			return nil, err
+		}
+		inArea = make(map[uuid.UUID]bool, len(nearby))
+		found.distances = make(map[uuid.UUID]*float64, len(nearby))
+		for _, venue := range nearby {
+			inArea[venue.VenueID] = true
+			found.distances[venue.VenueID] = venue.DistanceKm
+			found.ids = append(found.ids, venue.VenueID)
+		}
+	}
+
+	if query.Text == "" {
+		return found, nil
+	}
+
+	found.ids = nil
+	found.highlights = map[uuid.UUID]map[string]string{}
+	for _, hit := range vs.searchIndex.Search(query.Text, textCandidateLimit) {
+		id, err := uuid.Parse(hit.ID)
+		if err != nil || (inArea != nil && !inArea[id]) {
+			continue
+		}
+		found.ids = append(found.ids, id)
+		found.highlights[id] = hit.Highlights
+	}
+	return found, nil
+}
+
+// venueIDBatch bounds how many IDs one venue query filters on, which keeps its URL short
+const venueIDBatch = 200
+
+// queryVenuesByID runs query over every venue in query.IDs, a batch of IDs at a time, and
+// returns the matches in the column order of the sort
+func (vs *VenuesService) queryVenuesByID(ctx context.Context, query models.VenueSearchQuery) ([]*models.Venue, error) {
+	ids := query.IDs
+	matches := make([]*models.Venue, 0, len(ids))
+	for start := 0; start < len(ids); start += venueIDBatch {
+		query.IDs = ids[start:min(start+venueIDBatch, len(ids))]
+		batch, _, err := vs.venuesRepo.QueryVenues(ctx, query, 0, len(query.IDs), false)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, batch...)
+	}
+	// Each batch is sorted by the database; merging them needs one more pass
+	if len(ids) > venueIDBatch {
+		sortVenues(matches, query.Sort)
+	}
+	return matches, nil
+}
+
+// queryVenueCandidates runs the filters in query over the candidates of a search and sets their
+// distance and highlights. Distance and relevance sorts keep the order of the index that
+// found the venues, the scored sorts rank them, and the rest keep the database order.
+func (vs *VenuesService) queryVenueCandidates(ctx context.Context, query models.VenueSearchQuery, area *geo.Area, offset, limit int) ([]*models.Venue, int, error) {
+	found, err := vs.findCandidates(ctx, query, area)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(found.ids) == 0 {
+		return []*models.Venue{}, 0, nil
+	}
+	query.IDs = found.ids
+
+	// Matches come back in the column order of the sort, newest first for the others
+	matches, err := vs.queryVenuesByID(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if matches, err = vs.filterAvailable(ctx, matches, query); err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case query.Sort == models.SortRelevance:
+		matches = orderVenues(matches, found.ids)
+	case query.Sort == models.SortDistance:
+		ids := append([]uuid.UUID(nil), found.ids...)
+		sort.SliceStable(ids, func(i, j int) bool {
+			return *found.distances[ids[i]] < *found.distances[ids[j]]
+		})
+		matches = orderVenues(matches, ids)
+	case scoredSort(query.Sort):
+		ranked := make([]uuid.UUID, len(matches))
+		for i, venue := range matches {
+			ranked[i] = venue.Id
+		}
+		if err := vs.rankVenueIDs(ctx, query.Sort, ranked); err != nil {
+			return nil, 0, err
+		}
+		matches = orderVenues(matches, ranked)
+	}
+	for _, venue := range matches {
+		venue.DistanceKm = found.distances[venue.Id]
+		venue.Highlights = found.highlights[venue.Id]
+	}
+
+	total := len(matches)
+	if offset >= total {
+		return []*models.Venue{}, total, nil
+	}
+	return matches[offset:min(offset+limit, total)], total, nil
+}
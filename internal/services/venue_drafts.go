@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+var ErrVenueIncomplete = errors.New("venue listing is incomplete")
+
+// completenessCheck is one item of the listing wizard. Required items must be filled in
+// before a draft can be submitted; recommended ones only improve the score.
+type completenessCheck struct {
+	field    string
+	required bool
+	ok       func(v *models.Venue) bool
+}
+
+const (
+	requiredFieldWeight    = 3
+	recommendedFieldWeight = 1
+)
+
+var completenessChecks = []completenessCheck{
+	{"name", true, func(v *models.Venue) bool { return strings.TrimSpace(v.Name) != "" }},
+	{"description", true, func(v *models.Venue) bool { return strings.TrimSpace(v.Description) != "" }},
+	{"venue_type", true, func(v *models.Venue) bool { return len(v.VenueType) > 0 }},
+	{"region", true, func(v *models.Venue) bool { return strings.TrimSpace(v.Region) != "" }},
+	{"location", true, func(v *models.Venue) bool { return strings.TrimSpace(v.Location) != "" }},
+	{"coordinates", true, func(v *models.Venue) bool { return v.Coordinates.Latitude != 0 || v.Coordinates.Longitude != 0 }},
+	{"capacity", true, func(v *models.Venue) bool { return v.Capacity > 0 }},
+	{"pricing", true, func(v *models.Venue) bool {
+		// Checked on a copy so scoring never normalises the stored values
+		pricing := *v
+		return ValidateAndNormalizeVenuePricing(&pricing) == nil
+	}},
+	{"images", true, func(v *models.Venue) bool { return len(v.Images) > 0 }},
+	{"vibe_headline", false, func(v *models.Venue) bool { return strings.TrimSpace(v.VibeHeadline) != "" }},
+	{"amenities", false, func(v *models.Venue) bool { return len(v.Amenities) > 0 }},
+	{"rules", false, func(v *models.Venue) bool { return len(v.Rules) > 0 }},
+	{"accessibility", false, func(v *models.Venue) bool { return len(v.Accessibility) > 0 }},
+	{"tags", false, func(v *models.Venue) bool { return len(v.Tags) > 0 }},
+	{"cancellation_policy", false, func(v *models.Venue) bool { return strings.TrimSpace(v.CancellationPolicy) != "" }},
+}
+
+// venueCompleteness scores a listing against the wizard checks
+func venueCompleteness(v *models.Venue) *models.VenueCompleteness {
+	result := &models.VenueCompleteness{Missing: []string{}, Recommended: []string{}}
+	total, done := 0, 0
+	for _, check := range completenessChecks {
+		weight := recommendedFieldWeight
+		if check.required {
+			weight = requiredFieldWeight
+		}
+		total += weight
+
+		switch {
+		case check.ok(v):
+			done += weight
+		case check.required:
+			result.Missing = append(result.Missing, check.field)
+		default:
+			result.Recommended = append(result.Recommended, check.field)
+		}
+	}
+
+	result.Score = int(math.Round(100 * float64(done) / float64(total)))
+	result.Ready = len(result.Missing) == 0
+	return result
+}
+
+// attachCompleteness adds the wizard score to drafts
+func (vs *VenuesService) attachCompleteness(venue *models.Venue) {
+	if venue != nil && venue.Status == models.StatusDraft {
+		venue.Completeness = venueCompleteness(venue)
+	}
+}
+
+// venueSlug builds the slug of a venue; drafts without a name or location fall back to their ID
+func venueSlug(v *models.Venue) string {
+	if slug := helpers.GenerateSlug(v.Name, v.Location); slug != "" {
+		return slug
+	}
+	return v.Id.String()
+}
+
+// validateVenueDraft checks the fields a draft has so far; missing fields are fine
+func validateVenueDraft(v *models.Venue) error {
+	v.Name = helpers.StringTrim(v.Name)
+	v.Location = helpers.StringTrim(v.Location)
+	v.PriceModel = strings.ToUpper(helpers.StringTrim(v.PriceModel))
+	switch v.PriceModel {
+	case "", "HOURLY", "FIXED", "QUOTE_ONLY":
+	default:
+		return fmt.Errorf("%w: unsupported price_model %s", ErrInvalidVenueField, v.PriceModel)
+	}
+	return validateVenueValues(v)
+}
+
+// CreateVenueDraft saves a partial listing that only its host can see. Fields follow the
+// same rules as UpdateVenue; images are added through the gallery endpoints.
+func (vs *VenuesService) CreateVenueDraft(ctx context.Context, hostId uuid.UUID, fields map[string]interface{}, accessToken string) (*models.Venue, error) {
+	if hostId == uuid.Nil {
+		return nil, fmt.Errorf("invalid host ID")
+	}
+	for field := range fields {
+		if !editableVenueFields[field] {
+			return nil, fmt.Errorf("%w: %s cannot be set", ErrInvalidVenueField, field)
+		}
+	}
+
+	venue, err := mergeVenueUpdate(&models.Venue{}, fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVenueDraft(venue); err != nil {
+		return nil, err
+	}
+	if err := normalizeVenueAmenities(venue); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	venue.Id = uuid.New()
+	venue.HostId = hostId
+	venue.Slug = venueSlug(venue)
+	venue.Status = models.StatusDraft
+	venue.CreatedAt = now
+	venue.UpdatedAt = now
+
+	created, err := vs.venuesRepo.CreateVenue(ctx, venue, hostId, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.recordStatusChange(ctx, &models.VenueStatusChange{
+		VenueID:   created.Id,
+		To:        models.StatusDraft,
+		ActorID:   hostId,
+		CreatedAt: now,
+	})
+	vs.attachCompleteness(created)
+	return created, nil
+}
+
+// GetVenueCompleteness scores a venue against the listing wizard for its host or an admin
+func (vs *VenuesService) GetVenueCompleteness(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool) (*models.VenueCompleteness, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return venueCompleteness(venue), nil
+}
+
+// SubmitVenueDraft validates a finished draft in full and sends it to the review queue
+func (vs *VenuesService) SubmitVenueDraft(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if venue.Status != models.StatusDraft {
+		return nil, fmt.Errorf("%w: only drafts can be submitted", ErrInvalidStatusTransition)
+	}
+
+	if completeness := venueCompleteness(venue); !completeness.Ready {
+		return nil, fmt.Errorf("%w: missing %s", ErrVenueIncomplete, strings.Join(completeness.Missing, ", "))
+	}
+	if err := validateVenueUpdate(venue); err != nil {
+		return nil, err
+	}
+	reason := ""
+	if vs.moderateDescription(ctx, venue).Held() {
+		reason = venue.StatusReason
+	}
+	venue.Slug = venueSlug(venue)
+	venue.UpdatedAt = time.Now()
+
+	columns := append([]string{"name", "location", "description", "slug", "updated_at"}, pricingVenueFields...)
+	data, err := models.VenueColumns(venue, columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare venue update: %v", err)
+	}
+	change := statusColumns(data, venue, models.StatusPending, reason, userId)
+
+	updated, err := vs.venuesRepo.UpdateVenue(ctx, venue.HostId, venueId, data, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.recordStatusChange(ctx, change)
+	return updated, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// facetCandidateLimit bounds how many matching venues facets are counted over
+const facetCandidateLimit = 2000
+
+// facetBand is a price or capacity band offered as a filter chip
+type facetBand struct {
+	key      string
+	min, max float64
+}
+
+// Hourly price bands; the last one is open-ended
+var priceBands = []facetBand{
+	{"0-100", 0, 100},
+	{"100-250", 100, 250},
+	{"250-500", 250, 500},
+	{"500-1000", 500, 1000},
+	{"1000+", 1000, 0},
+}
+
+// Guest capacity bands; the last one is open-ended
+var capacityBands = []facetBand{
+	{"1-20", 1, 21},
+	{"21-50", 21, 51},
+	{"51-100", 51, 101},
+	{"101-250", 101, 251},
+	{"251+", 251, 0},
+}
+
+// QueryVenueFacets counts how many results each filter option would give for a search. Each
+// single-choice facet (venue type, region, price, capacity) is counted with every filter but
+// its own, so other options stay visible once one is picked; amenities combine, so they are
+// counted with every filter.
+func (vs *VenuesService) QueryVenueFacets(ctx context.Context, query models.VenueSearchQuery, area *geo.Area) (*models.VenueFacets, error) {
+	if err := prepareVenueSearch(&query, area); err != nil {
+		return nil, err
+	}
+
+	// The faceted filters are applied in Go, the rest by the database
+	base := query
+	base.VenueTypes, base.Region = nil, ""
+	base.MinPrice, base.MaxPrice = nil, nil
+	base.MinCapacity, base.MaxCapacity = nil, nil
+	base.Sort = ""
+
+	var venues []*models.Venue
+	if needsCandidates(query, area) {
+		found, err := vs.findCandidates(ctx, query, area)
+		if err != nil {
+			return nil, err
+		}
+		// Candidates are read a batch of IDs at a time, which keeps each query's URL short
+		for start := 0; start < len(found.ids) && len(venues) <= facetCandidateLimit; start += venueIDBatch {
+			base.IDs = found.ids[start:min(start+venueIDBatch, len(found.ids))]
+			batch, err := vs.venuesRepo.ListVenuesForFacets(ctx, base, facetCandidateLimit+1-len(venues))
+			if err != nil {
+				return nil, err
+			}
+			venues = append(venues, batch...)
+		}
+	} else {
+		var err error
+		venues, err = vs.venuesRepo.ListVenuesForFacets(ctx, base, facetCandidateLimit+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	partial := len(venues) > facetCandidateLimit
+	if partial {
+		venues = venues[:facetCandidateLimit]
+	}
+	venues, err := vs.filterAvailable(ctx, venues, base)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := countVenueFacets(venues, query)
+	facets.Partial = partial
+	return facets, nil
+}
+
+func countVenueFacets(venues []*models.Venue, query models.VenueSearchQuery) *models.VenueFacets {
+	venueTypes := map[string]int{}
+	regions := map[string]int{}
+	amenities := map[string]int{}
+	price := make([]int, len(priceBands))
+	capacity := make([]int, len(capacityBands))
+
+	for _, venue := range venues {
+		byType := matchesVenueTypes(venue, query.VenueTypes)
+		byRegion := query.Region == "" || strings.EqualFold(venue.Region, query.Region)
+		byPrice := matchesPrice(venue, query.MinPrice, query.MaxPrice)
+		byCapacity := (query.MinCapacity == nil || venue.Capacity >= *query.MinCapacity) &&
+			(query.MaxCapacity == nil || venue.Capacity <= *query.MaxCapacity)
+
+		if byRegion && byPrice && byCapacity {
+			for _, venueType := range venue.VenueType {
+				venueTypes[strings.ToLower(venueType)]++
+			}
+		}
+		if byType && byPrice && byCapacity && venue.Region != "" {
+			regions[venue.Region]++
+		}
+		if byType && byRegion && byCapacity && venue.PricePerHour > 0 {
+			if i := bandIndex(priceBands, venue.PricePerHour); i >= 0 {
+				price[i]++
+			}
+		}
+		if byType && byRegion && byPrice {
+			if i := bandIndex(capacityBands, float64(venue.Capacity)); i >= 0 {
+				capacity[i]++
+			}
+		}
+		if byType && byRegion && byPrice && byCapacity {
+			for name, value := range venue.Amenities {
+				// Amenities recorded as false, zero or empty are not offered
+				if models.AmenityOffered(value) {
+					amenities[strings.ToLower(name)]++
+				}
+			}
+		}
+	}
+
+	return &models.VenueFacets{
+		VenueTypes: facetCounts(venueTypes),
+		Regions:    facetCounts(regions),
+		Price:      bandCounts(priceBands, price),
+		Capacity:   bandCounts(capacityBands, capacity),
+		Amenities:  facetCounts(amenities),
+	}
+}
+
+// matchesVenueTypes mirrors the venue_type filter: any type containing any of the terms
+func matchesVenueTypes(venue *models.Venue, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	for _, venueType := range venue.VenueType {
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(venueType), strings.ToLower(term)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesPrice mirrors the price filters; venues without an hourly price never match them
+func matchesPrice(venue *models.Venue, low, high *float64) bool {
+	if low == nil && high == nil {
+		return true
+	}
+	if venue.PricePerHour <= 0 {
+		return false
+	}
+	return (low == nil || venue.PricePerHour >= *low) && (high == nil || venue.PricePerHour <= *high)
+}
+
+func bandIndex(bands []facetBand, value float64) int {
+	for i, band := range bands {
+		if value >= band.min && (band.max == 0 || value < band.max) {
+			return i
+		}
+	}
+	return -1
+}
+
+func bandCounts(bands []facetBand, counts []int) []models.RangeFacetCount {
+	result := make([]models.RangeFacetCount, len(bands))
+	for i, band := range bands {
+		result[i] = models.RangeFacetCount{Key: band.key, Min: band.min, Count: counts[i]}
+		if band.max > 0 {
+			upper := band.max
+			result[i].Max = &upper
+		}
+	}
+	return result
+}
+
+// facetCounts lists the counted values, most results first
+func facetCounts(counts map[string]int) []models.FacetCount {
+	result := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, models.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
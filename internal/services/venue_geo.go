@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+const (
+	// defaultSearchRadiusKm applies to point searches that give no radius or bounding box
+	defaultSearchRadiusKm = 50
+	// maxSearchRadiusKm bounds how far a point search may reach
+	maxSearchRadiusKm = 500
+)
+
+// HaversineLocator searches venue coordinates in memory. It needs no database support and
+// reads every venue location on each search, so it is only meant for development and tests;
+// production runs on PostGIS.
+type HaversineLocator struct {
+	venuesRepo models.VenuesRepo
+}
+
+func NewHaversineLocator(venuesRepo models.VenuesRepo) *HaversineLocator {
+	return &HaversineLocator{venuesRepo: venuesRepo}
+}
+
+func (l *HaversineLocator) LocateVenues(ctx context.Context, area geo.Area, limit int) ([]models.NearbyVenue, error) {
+	locations, err := l.venuesRepo.ListVenueLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nearby := make([]models.NearbyVenue, 0, len(locations))
+	for _, location := range locations {
+		if !area.Contains(location.Point) {
+			continue
+		}
+		found := models.NearbyVenue{VenueID: location.VenueID}
+		if area.Center != nil {
+			distance := geo.DistanceKm(*area.Center, location.Point)
+			found.DistanceKm = &distance
+		}
+		nearby = append(nearby, found)
+	}
+
+	if area.Center != nil {
+		sort.SliceStable(nearby, func(i, j int) bool {
+			return *nearby[i].DistanceKm < *nearby[j].DistanceKm
+		})
+	}
+	if limit > 0 && len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// searchArea fills in the default radius and caps the radius of a point search
+func searchArea(area geo.Area) (geo.Area, error) {
+	if area.Center != nil && area.Bounds == nil && area.RadiusKm == 0 {
+		area.RadiusKm = defaultSearchRadiusKm
+	}
+	if err := area.Validate(); err != nil {
+		return area, err
+	}
+	if area.RadiusKm > maxSearchRadiusKm {
+		area.RadiusKm = maxSearchRadiusKm
+	}
+	return area, nil
+}
+
+// locateInArea finds every venue in area, nearest first for point searches. The other
+// filters run on the result, so it is not cut short here.
+func (vs *VenuesService) locateInArea(ctx context.Context, area geo.Area) ([]models.NearbyVenue, error) {
+	area, err := searchArea(area)
+	if err != nil {
+		return nil, err
+	}
+	return vs.locator.LocateVenues(ctx, area, 0)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/joshua-takyi/ww/internal/geo"
+)
+
+func TestSearchArea(t *testing.T) {
+	accra := &geo.Point{Lat: 5.6037, Lng: -0.1870}
+	box := &geo.Bounds{MinLat: 4.5, MinLng: -3.3, MaxLat: 11.2, MaxLng: 1.2}
+
+	tests := []struct {
+		name       string
+		area       geo.Area
+		wantRadius float64
+		wantErr    bool
+	}{
+		{"point gets the default radius", geo.Area{Center: accra}, defaultSearchRadiusKm, false},
+		{"radius is kept", geo.Area{Center: accra, RadiusKm: 12}, 12, false},
+		{"radius is capped", geo.Area{Center: accra, RadiusKm: 5000}, maxSearchRadiusKm, false},
+		{"box needs no radius", geo.Area{Bounds: box}, 0, false},
+		{"point inside a box is not given a radius", geo.Area{Center: accra, Bounds: box}, 0, false},
+		{"no point or box", geo.Area{}, 0, true},
+		{"radius without a point", geo.Area{Bounds: box, RadiusKm: 10}, 0, true},
+		{"negative radius", geo.Area{Center: accra, RadiusKm: -1}, 0, true},
+		{"point out of range", geo.Area{Center: &geo.Point{Lat: 91, Lng: 0}}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchArea(tt.area)
+			if tt.wantErr {
+				if !errors.Is(err, geo.ErrInvalidArea) {
+					t.Fatalf("searchArea(%+v) error = %v, want ErrInvalidArea", tt.area, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchArea(%+v) unexpected error: %v", tt.area, err)
+			}
+			if got.RadiusKm != tt.wantRadius {
+				t.Errorf("searchArea(%+v).RadiusKm = %v, want %v", tt.area, got.RadiusKm, tt.wantRadius)
+			}
+		})
+	}
+}
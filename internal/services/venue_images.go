@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/imaging"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/storage"
+)
+
+var (
+	ErrNoVenueImages         = errors.New("at least one image is required")
+	ErrTooManyVenueImages    = errors.New("too many images for this venue")
+	ErrVenueImageTooLarge    = errors.New("image exceeds the maximum allowed size")
+	ErrUnsupportedVenueImage = errors.New("image must be a JPEG, PNG or WebP file")
+	ErrVenueImageNotFound    = errors.New("image not found on this venue")
+	ErrInvalidImageOrder     = errors.New("image order must list every current image exactly once")
+	ErrVenueImagesOnCreate   = errors.New("images are uploaded to a venue after it is created")
+)
+
+// galleryImage is a venue image with everything stored for it
+type galleryImage struct {
+	URL      string
+	PublicID string
+	Variants models.ImageVariants
+}
+
+// venueGallery returns the venue's images with their storage IDs and variants. Venues
+// created before public IDs were stored have them derived from their URLs.
+func (vs *VenuesService) venueGallery(venue *models.Venue) []galleryImage {
+	gallery := make([]galleryImage, len(venue.Images))
+	for i, url := range venue.Images {
+		gallery[i].URL = url
+		if i < len(venue.ImagePublicIDs) && venue.ImagePublicIDs[i] != "" {
+			gallery[i].PublicID = venue.ImagePublicIDs[i]
+		} else if vs.media != nil {
+			gallery[i].PublicID = vs.media.PublicIDFromURL(url)
+		}
+		if i < len(venue.ImageVariants) {
+			gallery[i].Variants = venue.ImageVariants[i]
+		}
+	}
+	return gallery
+}
+
+// AddVenueImages validates and uploads new gallery images, appending them after the existing ones
+func (vs *VenuesService) AddVenueImages(ctx context.Context, userId, venueId uuid.UUID, files []*multipart.FileHeader, isAdmin bool, accessToken string) (*models.Venue, error) {
+	if len(files) == 0 {
+		return nil, ErrNoVenueImages
+	}
+	if vs.media == nil {
+		return nil, fmt.Errorf("image storage is not configured")
+	}
+
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if vs.settings.MaxImages > 0 && len(venue.Images)+len(files) > vs.settings.MaxImages {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyVenueImages, vs.settings.MaxImages)
+	}
+
+	// Validate every file before uploading any of them
+	for _, fh := range files {
+		if vs.settings.MaxImageBytes > 0 && fh.Size > vs.settings.MaxImageBytes {
+			return nil, fmt.Errorf("%w: %s", ErrVenueImageTooLarge, fh.Filename)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image %s: %v", fh.Filename, err)
+		}
+		_, sniffErr := helpers.DetectImageType(f)
+		f.Close()
+		if sniffErr != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedVenueImage, fh.Filename)
+		}
+	}
+
+	added := make([]galleryImage, 0, len(files))
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			vs.deleteVenueAssets(ctx, added)
+			return nil, fmt.Errorf("failed to read image %s: %v", fh.Filename, err)
+		}
+		image, err := vs.storeVenueImage(ctx, f)
+		f.Close()
+		if err != nil {
+			vs.deleteVenueAssets(ctx, added)
+			return nil, venueImageError(err, fh.Filename)
+		}
+		added = append(added, image)
+	}
+
+	gallery := append(vs.venueGallery(venue), added...)
+	updated, err := vs.saveNewVenueImages(ctx, venue, gallery, userId, accessToken)
+	if err != nil {
+		vs.deleteVenueAssets(ctx, added)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// ReorderVenueImages rearranges the gallery; order must contain every current image URL once
+func (vs *VenuesService) ReorderVenueImages(ctx context.Context, userId, venueId uuid.UUID, order []string, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) != len(venue.Images) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	current := vs.venueGallery(venue)
+	position := make(map[string]int, len(current))
+	for i, image := range current {
+		position[image.URL] = i
+	}
+
+	gallery := make([]galleryImage, 0, len(order))
+	seen := make(map[string]bool, len(order))
+	for _, url := range order {
+		i, ok := position[url]
+		if !ok || seen[url] {
+			return nil, ErrInvalidImageOrder
+		}
+		seen[url] = true
+		gallery = append(gallery, current[i])
+	}
+
+	return vs.saveVenueGallery(ctx, venue, gallery, accessToken)
+}
+
+// SetVenueCoverImage moves the image at index to the front of the gallery, making it the cover
+func (vs *VenuesService) SetVenueCoverImage(ctx context.Context, userId, venueId uuid.UUID, index int, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(venue.Images) {
+		return nil, ErrVenueImageNotFound
+	}
+	if index == 0 {
+		return venue, nil
+	}
+
+	current := vs.venueGallery(venue)
+	gallery := make([]galleryImage, 0, len(current))
+	gallery = append(gallery, current[index])
+	gallery = append(gallery, current[:index]...)
+	gallery = append(gallery, current[index+1:]...)
+
+	return vs.saveVenueGallery(ctx, venue, gallery, accessToken)
+}
+
+// RemoveVenueImage deletes the image at index from the gallery and from storage
+func (vs *VenuesService) RemoveVenueImage(ctx context.Context, userId, venueId uuid.UUID, index int, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(venue.Images) {
+		return nil, ErrVenueImageNotFound
+	}
+
+	current := vs.venueGallery(venue)
+	removed := current[index]
+	gallery := append(append([]galleryImage{}, current[:index]...), current[index+1:]...)
+
+	updated, err := vs.saveVenueGallery(ctx, venue, gallery, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Storage is cleaned up only once the venue no longer references the image
+	vs.deleteVenueAssets(ctx, []galleryImage{removed})
+	return updated, nil
+}
+
+// hasVenueImages reports whether a venue sent by a client already names gallery images. The
+// gallery is only filled from uploads, never from paths or URLs the client chose.
+func hasVenueImages(venue *models.Venue) bool {
+	return len(venue.Images) > 0 || len(venue.ImagePublicIDs) > 0 || len(venue.ImageVariants) > 0
+}
+
+func (vs *VenuesService) storeVenueImage(ctx context.Context, r io.Reader) (galleryImage, error) {
+	asset, variants, err := storeImage(ctx, vs.media, r, storage.VenueFolder, imaging.VenueSpecs)
+	if err != nil {
+		return galleryImage{}, err
+	}
+	return galleryImage{URL: asset.URL, PublicID: asset.PublicID, Variants: variants}, nil
+}
+
+// venueImageError maps image processing failures onto the venue image errors
+func venueImageError(err error, name string) error {
+	switch {
+	case errors.Is(err, imaging.ErrUnsupportedImage):
+		return fmt.Errorf("%w: %s", ErrUnsupportedVenueImage, name)
+	case errors.Is(err, imaging.ErrImageTooLarge):
+		return fmt.Errorf("%w: %s", ErrVenueImageTooLarge, name)
+	default:
+		return err
+	}
+}
+
+// applyGallery sets the venue's image columns from gallery
+func applyGallery(venue *models.Venue, gallery []galleryImage) {
+	venue.Images = make([]string, len(gallery))
+	venue.ImagePublicIDs = make([]string, len(gallery))
+	venue.ImageVariants = make([]models.ImageVariants, len(gallery))
+	for i, image := range gallery {
+		venue.Images[i] = image.URL
+		venue.ImagePublicIDs[i] = image.PublicID
+		venue.ImageVariants[i] = image.Variants
+	}
+}
+
+func galleryColumns(gallery []galleryImage) map[string]interface{} {
+	var columns models.Venue
+	applyGallery(&columns, gallery)
+	return map[string]interface{}{
+		"images":           columns.Images,
+		"image_public_ids": columns.ImagePublicIDs,
+		"image_variants":   columns.ImageVariants,
+		"updated_at":       time.Now(),
+	}
+}
+
+func (vs *VenuesService) saveVenueGallery(ctx context.Context, venue *models.Venue, gallery []galleryImage, accessToken string) (*models.Venue, error) {
+	return vs.venuesRepo.UpdateVenue(ctx, venue.HostId, venue.Id, galleryColumns(gallery), accessToken)
+}
+
+// saveNewVenueImages saves a gallery with added images; new images have not been approved,
+// so the venue goes back to the review queue
+func (vs *VenuesService) saveNewVenueImages(ctx context.Context, venue *models.Venue, gallery []galleryImage, actorId uuid.UUID, accessToken string) (*models.Venue, error) {
+	data := galleryColumns(gallery)
+	change := resubmitColumns(data, venue, actorId)
+	updated, err := vs.venuesRepo.UpdateVenue(ctx, venue.HostId, venue.Id, data, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	vs.recordStatusChange(ctx, change)
+	return updated, nil
+}
+
+// deleteVenueAssets removes venue images and their variants from storage; failures are only logged
+func (vs *VenuesService) deleteVenueAssets(ctx context.Context, gallery []galleryImage) {
+	if len(gallery) == 0 || vs.media == nil {
+		return
+	}
+	var ids []string
+	for _, image := range gallery {
+		ids = append(ids, imageAssetIDs(image.PublicID, image.Variants)...)
+	}
+	if err := vs.media.Delete(ctx, ids...); err != nil {
+		fmt.Printf("Failed to delete venue images: %v\n", err)
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/helpers"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+var (
+	ErrInvalidStatusTransition = errors.New("venue status cannot change this way")
+	ErrVenueRejectionReason    = errors.New("a reason is required when rejecting a venue")
+	ErrVenueSuspended          = errors.New("venue was deactivated by an admin and can only be reactivated by one")
+	ErrInvalidVenueStatus      = errors.New("invalid venue status")
+)
+
+// venueTransitions lists the statuses each status may move to.
+// Drafts are being written by their host, pending venues wait for an admin, active ones are public, inactive ones were taken down by
+// their host (or an admin) and rejected ones were turned down until the host edits them.
+var venueTransitions = map[models.VenueStatus][]models.VenueStatus{
+	models.StatusDraft:    {models.StatusPending},
+	models.StatusPending:  {models.StatusActive, models.StatusRejected},
+	models.StatusActive:   {models.StatusInactive, models.StatusPending},
+	models.StatusInactive: {models.StatusActive, models.StatusPending},
+	models.StatusRejected: {models.StatusPending},
+}
+
+// reviewedVenueFields are the details an admin signs off on; changing any of them sends the
+// venue back to the review queue
+var reviewedVenueFields = []string{
+	"name",
+	"vibe_headline",
+	"description",
+	"venue_type",
+	"region",
+	"location",
+	"coordinates",
+}
+
+const (
+	// statusHistoryLimit bounds how many status changes are returned at once
+	statusHistoryLimit = 100
+	// resubmitReason is recorded when an edit sends a venue back for review
+	resubmitReason = "venue details changed"
+	// heldDescriptionReason starts the status reason of a pending venue whose description
+	// was held by moderation
+	heldDescriptionReason = "description held for review"
+)
+
+func canTransition(from, to models.VenueStatus) bool {
+	for _, next := range venueTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// touchesReviewedFields reports whether an update changes details that need re-approval
+func touchesReviewedFields(updates map[string]interface{}) bool {
+	for _, field := range reviewedVenueFields {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// statusColumns sets the lifecycle columns of a venue update and returns the change to record
+// once the write succeeds
+func statusColumns(data map[string]interface{}, venue *models.Venue, to models.VenueStatus, reason string, actorId uuid.UUID) *models.VenueStatusChange {
+	now := time.Now()
+	data["status"] = to
+	data["status_reason"] = reason
+	data["status_changed_at"] = now
+	return &models.VenueStatusChange{
+		VenueID:   venue.Id,
+		From:      venue.Status,
+		To:        to,
+		Reason:    reason,
+		ActorID:   actorId,
+		CreatedAt: now,
+	}
+}
+
+// resubmitColumns sends a venue that is not already waiting for review back to the queue.
+// It returns nil when the venue is already pending, or still a draft that has not been submitted.
+func resubmitColumns(data map[string]interface{}, venue *models.Venue, actorId uuid.UUID) *models.VenueStatusChange {
+	if venue.Status == models.StatusPending || venue.Status == models.StatusDraft || !canTransition(venue.Status, models.StatusPending) {
+		return nil
+	}
+	return statusColumns(data, venue, models.StatusPending, resubmitReason, actorId)
+}
+
+// recordStatusChange adds a change to the venue's status history; failures are only logged
+func (vs *VenuesService) recordStatusChange(ctx context.Context, change *models.VenueStatusChange) {
+	if change == nil {
+		return
+	}
+	if err := vs.statusHistoryRepo.RecordVenueStatusChange(ctx, change); err != nil {
+		fmt.Printf("Failed to record status change for venue %s: %v\n", change.VenueID, err)
+	}
+}
+
+// changeVenueStatus moves a venue to a new status if the lifecycle allows it. Hosts change
+// their own venues with their access token; asAdmin changes go through the service role, so
+// an admin acting on someone else's venue does not depend on row level security.
+func (vs *VenuesService) changeVenueStatus(ctx context.Context, venue *models.Venue, to models.VenueStatus, reason string, actorId uuid.UUID, asAdmin bool, accessToken string) (*models.Venue, error) {
+	if !canTransition(venue.Status, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, venue.Status, to)
+	}
+
+	data := map[string]interface{}{}
+	change := statusColumns(data, venue, to, reason, actorId)
+	var updated *models.Venue
+	var err error
+	if asAdmin {
+		updated, err = vs.venuesRepo.AdminUpdateVenue(ctx, venue.Id, data)
+	} else {
+		updated, err = vs.venuesRepo.UpdateVenue(ctx, venue.HostId, venue.Id, data, accessToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vs.recordStatusChange(ctx, change)
+	return updated, nil
+}
+
+// ReviewVenue approves or rejects a venue waiting in the review queue (admin only)
+func (vs *VenuesService) ReviewVenue(ctx context.Context, adminId, venueId uuid.UUID, approve bool, reason string) (*models.Venue, error) {
+	if adminId == uuid.Nil || venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid admin ID or venue ID")
+	}
+
+	reason = helpers.StringTrim(reason)
+	to := models.StatusActive
+	if !approve {
+		to = models.StatusRejected
+		if reason == "" {
+			return nil, ErrVenueRejectionReason
+		}
+	}
+
+	venue, err := vs.venuesRepo.ListVenueByID(ctx, venueId)
+	if err != nil {
+		return nil, err
+	}
+	if venue.IsDeleted() {
+		return nil, models.ErrVenueNotFound
+	}
+	if venue.Status != models.StatusPending {
+		return nil, fmt.Errorf("%w: only pending venues can be reviewed", ErrInvalidStatusTransition)
+	}
+
+	return vs.changeVenueStatus(ctx, venue, to, reason, adminId, true, "")
+}
+
+// DeactivateVenue takes an active venue off the public listings
+func (vs *VenuesService) DeactivateVenue(ctx context.Context, userId, venueId uuid.UUID, reason string, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if venue.Status != models.StatusActive {
+		return nil, fmt.Errorf("%w: only active venues can be deactivated", ErrInvalidStatusTransition)
+	}
+
+	return vs.changeVenueStatus(ctx, venue, models.StatusInactive, helpers.StringTrim(reason), userId, actsAsAdmin(venue, userId, isAdmin), accessToken)
+}
+
+// ReactivateVenue puts a deactivated venue back on the public listings. Venues taken down by
+// an admin stay down until an admin reactivates them.
+func (vs *VenuesService) ReactivateVenue(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool, accessToken string) (*models.Venue, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if venue.Status != models.StatusInactive {
+		return nil, fmt.Errorf("%w: only inactive venues can be reactivated", ErrInvalidStatusTransition)
+	}
+
+	if !isAdmin {
+		history, err := vs.statusHistoryRepo.GetVenueStatusHistory(ctx, venueId, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) > 0 && history[0].To == models.StatusInactive && history[0].ActorID != venue.HostId {
+			return nil, ErrVenueSuspended
+		}
+	}
+
+	return vs.changeVenueStatus(ctx, venue, models.StatusActive, "", userId, actsAsAdmin(venue, userId, isAdmin), accessToken)
+}
+
+// GetVenueReviewQueue lists venues in a lifecycle status for admins, pending by default
+func (vs *VenuesService) GetVenueReviewQueue(ctx context.Context, status string, offset, limit int) ([]*models.Venue, int, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, 0, fmt.Errorf("invalid offset or limit")
+	}
+
+	queue := models.StatusPending
+	if status != "" {
+		queue = models.VenueStatus(status)
+		if _, ok := venueTransitions[queue]; !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrInvalidVenueStatus, status)
+		}
+	}
+
+	venues, total, err := vs.venuesRepo.ListVenuesByStatus(ctx, queue, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, venue := range venues {
+		venue.DescriptionHeld = queue == models.StatusPending && descriptionHeld(venue)
+	}
+	return venues, total, nil
+}
+
+// descriptionHeld reports whether the venue waits for review because moderation held its description
+func descriptionHeld(venue *models.Venue) bool {
+	return strings.HasPrefix(venue.StatusReason, heldDescriptionReason)
+}
+
+// GetVenueStatusHistory returns a venue's lifecycle changes, newest first, to its host or an admin
+func (vs *VenuesService) GetVenueStatusHistory(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool) ([]*models.VenueStatusChange, error) {
+	if _, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin); err != nil {
+		return nil, err
+	}
+	return vs.statusHistoryRepo.GetVenueStatusHistory(ctx, venueId, statusHistoryLimit)
+}
+
+// actsAsAdmin reports whether a change to venue is made by an admin who does not host it
+func actsAsAdmin(venue *models.Venue, userId uuid.UUID, isAdmin bool) bool {
+	return isAdmin && venue.HostId != userId
+}
+
+// canViewVenue reports whether a venue is visible to the viewer: active venues are public,
+// every other status only to the venue's host and admins
+func canViewVenue(venue *models.Venue, viewerId uuid.UUID, isAdmin bool) bool {
+	return venue.Status == models.StatusActive || isAdmin || (viewerId != uuid.Nil && venue.HostId == viewerId)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/search"
+)
+
+// ValidateVenueSearch checks a search the way QueryVenues would before running it
+func ValidateVenueSearch(query models.VenueSearchQuery, area *geo.Area) error {
+	if err := prepareVenueSearch(&query, area); err != nil {
+		return err
+	}
+	if area != nil {
+		if _, err := searchArea(*area); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchVenues returns which of the given venues a search would return, in no particular order.
+// Text is matched against an index of just those venues, so venues the shared index has not
+// picked up yet are matched too.
+func (vs *VenuesService) MatchVenues(ctx context.Context, query models.VenueSearchQuery, area *geo.Area, ids []uuid.UUID) ([]*models.Venue, error) {
+	if len(ids) == 0 {
+		return []*models.Venue{}, nil
+	}
+	if err := prepareVenueSearch(&query, area); err != nil {
+		return nil, err
+	}
+	// Only membership matters here, not the order of the results
+	query.Sort = ""
+	query.IDs = ids
+
+	// Only the given venues matter, so the area is checked on their own positions rather
+	// than by locating every venue in it
+	var inArea *geo.Area
+	if area != nil {
+		checked, err := searchArea(*area)
+		if err != nil {
+			return nil, err
+		}
+		inArea = &checked
+	}
+
+	matches, err := vs.queryVenuesByID(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if inArea != nil {
+		matches = venuesInArea(matches, *inArea)
+	}
+
+	if query.Text != "" && len(matches) > 0 {
+		index := search.NewIndex(venueSearchFields)
+		docs := make([]search.Document, len(matches))
+		for i, venue := range matches {
+			docs[i] = venueSearchDocument(venue)
+		}
+		index.Rebuild(docs)
+
+		hits := index.Search(query.Text, len(matches))
+		found := make([]uuid.UUID, 0, len(hits))
+		for _, hit := range hits {
+			if id, err := uuid.Parse(hit.ID); err == nil {
+				found = append(found, id)
+			}
+		}
+		matches = orderVenues(matches, found)
+	}
+
+	return vs.filterAvailable(ctx, matches, query)
+}
+
+// venuesInArea keeps the venues placed inside area. Zero coordinates mean the host never
+// placed the venue on the map, so such venues are in no area.
+func venuesInArea(venues []*models.Venue, area geo.Area) []*models.Venue {
+	kept := venues[:0]
+	for _, venue := range venues {
+		point := geo.Point{Lat: venue.Coordinates.Latitude, Lng: venue.Coordinates.Longitude}
+		if (point.Lat != 0 || point.Lng != 0) && area.Contains(point) {
+			kept = append(kept, venue)
+		}
+	}
+	return kept
+}
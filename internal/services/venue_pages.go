@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/joshua-takyi/ww/internal/geo"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// pageVenues returns the page of a validated search that page asks for. Searches the database
+// orders by column are paged by key; those ranked in memory hold an offset in their cursors.
+func (vs *VenuesService) pageVenues(ctx context.Context, query models.VenueSearchQuery, area *geo.Area, page models.PageRequest) ([]*models.Venue, models.PageInfo, error) {
+	scope := venueSearchScope(query, area)
+	cursor, err := models.DecodeCursor(page.Cursor, scope)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	if pagedByKey(query, area) {
+		return vs.pageVenuesByKey(ctx, query, scope, cursor, page)
+	}
+	offset := page.Offset
+	if cursor != nil {
+		offset = cursor.Offset
+	}
+
+	// One extra venue tells whether another page follows without counting
+	var venues []*models.Venue
+	var total int
+	var truncated bool
+	if needsCandidates(query, area) {
+		venues, total, err = vs.queryVenueCandidates(ctx, query, area, offset, page.Limit+1)
+	} else {
+		venues, total, truncated, err = vs.searchVenues(ctx, query, offset, page.Limit+1, page.WithTotal)
+	}
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	venues, hasMore := models.TrimPage(venues, page.Limit, false)
+	info := models.OffsetPage(scope, offset, page.Limit, hasMore)
+	info.Truncated = truncated
+	if page.WithTotal {
+		info.Total = total
+	}
+	return venues, info, nil
+}
+
+// pagedByKey reports whether the database returns a search in its final order, so its pages
+// can start from the key of a venue instead of an offset
+func pagedByKey(query models.VenueSearchQuery, area *geo.Area) bool {
+	return !needsCandidates(query, area) && !scoredSort(query.Sort) && query.Window == nil
+}
+
+// pageVenuesByKey pages a search in the column order of its sort. Without a cursor the first
+// page still honours the request offset, for clients that page by offset.
+func (vs *VenuesService) pageVenuesByKey(ctx context.Context, query models.VenueSearchQuery, scope string, cursor *models.Cursor, page models.PageRequest) ([]*models.Venue, models.PageInfo, error) {
+	if cursor != nil && cursor.Key == nil {
+		return nil, models.PageInfo{}, models.ErrInvalidCursor
+	}
+
+	var venues []*models.Venue
+	var total int
+	var err error
+	if cursor == nil && page.Offset > 0 {
+		venues, total, err = vs.venuesRepo.QueryVenues(ctx, query, page.Offset, page.Limit+1, page.WithTotal)
+	} else {
+		venues, total, err = vs.venuesRepo.QueryVenuesByKey(ctx, query, cursor, page.Limit+1, page.WithTotal)
+	}
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	venues, hasMore := models.TrimPage(venues, page.Limit, cursor != nil && cursor.Backward)
+	var info models.PageInfo
+	if len(venues) > 0 {
+		first := models.VenueCursorKey(venues[0], query.Sort)
+		last := models.VenueCursorKey(venues[len(venues)-1], query.Sort)
+		info = models.KeysetPage(scope, cursor, first, last, hasMore)
+	}
+	if page.WithTotal {
+		info.Total = total
+	}
+	return venues, info, nil
+}
+
+// venueSearchScope identifies a search so its cursors cannot be replayed against another one
+func venueSearchScope(query models.VenueSearchQuery, area *geo.Area) string {
+	data, _ := json.Marshal(struct {
+		Query models.VenueSearchQuery
+		Area  *geo.Area
+	}{query, area})
+	sum := sha256.Sum256(data)
+	return "venues:" + hex.EncodeToString(sum[:8])
+}
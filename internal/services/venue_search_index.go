@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/search"
+)
+
+const (
+	// textCandidateLimit bounds how many venues a text search considers before the other
+	// filters run; only the most relevant ones are kept
+	textCandidateLimit = 500
+	// searchIndexBatch is how many venues are read per request while rebuilding the index
+	searchIndexBatch = 200
+	// maxSuggestions bounds how many completions one request returns
+	maxSuggestions = 20
+	// maxSuggestPrefixLength bounds what is completed; longer input is a search, not a prefix
+	maxSuggestPrefixLength = 100
+)
+
+// venueSearchFields are the venue fields text searches look at, heaviest first
+var venueSearchFields = []search.Field{
+	{Name: "name", Weight: 3},
+	{Name: "vibe_headline", Weight: 2},
+	{Name: "tags", Weight: 2},
+	{Name: "location", Weight: 1.5},
+	{Name: "description", Weight: 1},
+}
+
+func newVenueSearchIndex() *search.Index {
+	return search.NewIndex(venueSearchFields)
+}
+
+func venueSearchDocument(venue *models.Venue) search.Document {
+	return search.Document{
+		ID: venue.Id.String(),
+		Fields: map[string]string{
+			"name":          venue.Name,
+			"vibe_headline": venue.VibeHeadline,
+			"tags":          strings.Join(venue.Tags, ", "),
+			"location":      venue.Location,
+			"description":   venue.Description,
+		},
+	}
+}
+
+// RebuildSearchIndex indexes every active venue for text search and suggestions, and returns
+// how many there were. Venues that have since been unpublished stay findable until the next
+// rebuild, but searches only ever return active venues, so they simply drop out of the results.
+func (vs *VenuesService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	var docs []search.Document
+	suggestions := newSuggestionSet()
+	for offset := 0; ; offset += searchIndexBatch {
+		venues, _, err := vs.venuesRepo.ListVenuesByStatus(ctx, models.StatusActive, offset, searchIndexBatch)
+		if err != nil {
+			return 0, err
+		}
+
+		ids := make([]string, len(venues))
+		for i, venue := range venues {
+			ids[i] = venue.Id.String()
+		}
+		views, err := vs.venueViewsRepo.CountVenueViews(ctx, ids)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, venue := range venues {
+			docs = append(docs, venueSearchDocument(venue))
+			suggestions.addVenue(venue, float64(1+views[venue.Id.String()]))
+		}
+		if len(venues) < searchIndexBatch {
+			break
+		}
+	}
+
+	vs.searchIndex.Rebuild(docs)
+	vs.suggester.Rebuild(suggestions.entries)
+	return len(docs), nil
+}
+
+// suggestionSet collects suggestions, merging those naming the same thing so that a region
+// weighs as much as all its venues together
+type suggestionSet struct {
+	entries []search.Suggestion
+	index   map[string]int
+}
+
+func newSuggestionSet() *suggestionSet {
+	return &suggestionSet{index: map[string]int{}}
+}
+
+// addVenue offers the venue's name and what it can be found by, weighted by its popularity
+func (s *suggestionSet) addVenue(venue *models.Venue, weight float64) {
+	if venue.Name != "" {
+		s.entries = append(s.entries, search.Suggestion{Kind: models.SuggestVenue, Text: venue.Name, Ref: venue.Slug, Weight: weight})
+	}
+	s.add(models.SuggestRegion, venue.Region, weight)
+	s.add(models.SuggestLocation, venue.Location, weight)
+	for _, venueType := range venue.VenueType {
+		s.add(models.SuggestVenueType, venueType, weight)
+	}
+	for _, tag := range venue.Tags {
+		s.add(models.SuggestTag, tag, weight)
+	}
+}
+
+func (s *suggestionSet) add(kind, text string, weight float64) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	key := kind + ":" + strings.ToLower(text)
+	if i, ok := s.index[key]; ok {
+		s.entries[i].Weight += weight
+		return
+	}
+	s.index[key] = len(s.entries)
+	s.entries = append(s.entries, search.Suggestion{Kind: kind, Text: text, Weight: weight})
+}
+
+// SuggestSearches completes a partly typed search with venue names, regions, locations, venue
+// types and tags, the most popular first
+func (vs *VenuesService) SuggestSearches(ctx context.Context, prefix string, limit int) ([]models.SearchSuggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: q is required", models.ErrInvalidVenueSearch)
+	}
+	if len(prefix) > maxSuggestPrefixLength {
+		return nil, fmt.Errorf("%w: q is too long", models.ErrInvalidVenueSearch)
+	}
+	if limit <= 0 || limit > maxSuggestions {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", models.ErrInvalidVenueSearch, maxSuggestions)
+	}
+
+	found := vs.suggester.Suggest(prefix, limit)
+	suggestions := make([]models.SearchSuggestion, len(found))
+	for i, suggestion := range found {
+		suggestions[i] = models.SearchSuggestion{Type: suggestion.Kind, Text: suggestion.Text, Slug: suggestion.Ref}
+	}
+	return suggestions, nil
+}
+
+// WatchSearchIndex builds the search index, then rebuilds it every interval until ctx is done.
+// A non-positive interval builds it once.
+func (vs *VenuesService) WatchSearchIndex(ctx context.Context, interval time.Duration) {
+	rebuild := func() {
+		if _, err := vs.RebuildSearchIndex(ctx); err != nil {
+			fmt.Printf("[venues] failed to rebuild search index: %v\n", err)
+		}
+	}
+
+	rebuild()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rebuild()
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+// scoreSortCandidateLimit bounds how many matching venues are ranked for sorts the database
+// cannot apply. Venues beyond it, newest first, are not ranked; pages then say they are
+// truncated.
+const scoreSortCandidateLimit = 1000
+
+// scoredSort reports whether s ranks venues by data kept outside the venues table
+func scoredSort(s models.VenueSort) bool {
+	return s == models.SortPopularity
+}
+
+// searchVenues runs a validated query, ranking venues in Go when the sort needs it and
+// checking availability windows on the matches. truncated is set when only the newest
+// scoreSortCandidateLimit matches were ranked, or only the first availabilityCandidateLimit
+// were checked against the window.
+func (vs *VenuesService) searchVenues(ctx context.Context, query models.VenueSearchQuery, offset, limit int, withTotal bool) (venues []*models.Venue, total int, truncated bool, err error) {
+	if !scoredSort(query.Sort) && query.Window == nil {
+		venues, total, err = vs.venuesRepo.QueryVenues(ctx, query, offset, limit, withTotal)
+		return venues, total, false, err
+	}
+
+	var ids []uuid.UUID
+	if query.Window != nil {
+		ids, truncated, err = vs.availableVenueIDs(ctx, query)
+		total = len(ids)
+	} else {
+		// One extra ID tells whether some matches are left unranked
+		ids, total, err = vs.venuesRepo.SearchVenueIDs(ctx, query, scoreSortCandidateLimit+1, withTotal)
+		if len(ids) > scoreSortCandidateLimit {
+			ids, truncated = ids[:scoreSortCandidateLimit], true
+		}
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if scoredSort(query.Sort) {
+		if err := vs.rankVenueIDs(ctx, query.Sort, ids); err != nil {
+			return nil, 0, false, err
+		}
+	}
+
+	if offset >= len(ids) {
+		return []*models.Venue{}, total, truncated, nil
+	}
+	page := ids[offset:min(offset+limit, len(ids))]
+
+	venues, _, err = vs.venuesRepo.QueryVenues(ctx, models.VenueSearchQuery{IDs: page}, 0, len(page), false)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return orderVenues(venues, page), total, truncated, nil
+}
+
+// rankVenueIDs stably sorts ids by their score for s, higher first; venues with equal scores
+// keep their current order, which the database made deterministic
+func (vs *VenuesService) rankVenueIDs(ctx context.Context, s models.VenueSort, ids []uuid.UUID) error {
+	scores := make(map[uuid.UUID]float64, len(ids))
+
+	switch s {
+	case models.SortPopularity:
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = id.String()
+		}
+		views, err := vs.venueViewsRepo.CountVenueViews(ctx, keys)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			scores[id] = float64(views[id.String()])
+		}
+	default:
+		return fmt.Errorf("sort %q is not ranked by score", s)
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		return scores[ids[i]] > scores[ids[j]]
+	})
+	return nil
+}
+
+// sortVenues orders venues the way the database orders a column sort: by the sorted column,
+// newest first for every other sort, then by ID
+func sortVenues(venues []*models.Venue, s models.VenueSort) {
+	sort.SliceStable(venues, func(i, j int) bool {
+		a, b := venues[i], venues[j]
+		switch s {
+		case models.SortPriceAsc, models.SortPriceDesc:
+			if a.PricePerHour != b.PricePerHour {
+				return (a.PricePerHour < b.PricePerHour) == (s == models.SortPriceAsc)
+			}
+		case models.SortCapacityAsc, models.SortCapacityDesc:
+			if a.Capacity != b.Capacity {
+				return (a.Capacity < b.Capacity) == (s == models.SortCapacityAsc)
+			}
+		case models.SortRating:
+			if a.RatingAverage != b.RatingAverage {
+				return a.RatingAverage > b.RatingAverage
+			}
+			if a.RatingCount != b.RatingCount {
+				return a.RatingCount > b.RatingCount
+			}
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+		}
+		return bytes.Compare(a.Id[:], b.Id[:]) < 0
+	})
+}
+
+// orderVenues returns venues in the order of ids, leaving out those not in it
+func orderVenues(venues []*models.Venue, ids []uuid.UUID) []*models.Venue {
+	byID := make(map[uuid.UUID]*models.Venue, len(venues))
+	for _, venue := range venues {
+		byID[venue.Id] = venue
+	}
+
+	ordered := make([]*models.Venue, 0, len(venues))
+	for _, id := range ids {
+		if venue, ok := byID[id]; ok {
+			ordered = append(ordered, venue)
+		}
+	}
+	return ordered
+}
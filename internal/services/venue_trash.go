@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+)
+
+var (
+	ErrVenueHasBookings    = models.ErrVenueBooked
+	ErrVenueNotDeleted     = errors.New("venue is not deleted")
+	ErrRestoreWindowClosed = errors.New("venue can no longer be restored")
+)
+
+const (
+	// venuePurgeBatch bounds the venues purged per pass
+	venuePurgeBatch = 50
+	// bookingsDeactivationReason is recorded when a delete request is turned into a deactivation
+	bookingsDeactivationReason = "deletion requested while bookings are upcoming"
+)
+
+// VenueDeletion reports the outcome of DeleteVenue
+type VenueDeletion struct {
+	Venue *models.Venue `json:"venue"`
+	// Deactivated is set when upcoming bookings kept the venue from being deleted and it
+	// was taken off the listings instead
+	Deactivated bool `json:"deactivated"`
+	// RestoreUntil is when a deleted venue is purged for good
+	RestoreUntil *time.Time `json:"restore_until,omitempty"`
+}
+
+// DeleteVenue moves a venue to its host's trash, where it can be restored until the restore
+// window passes. Venues with upcoming pending or confirmed bookings cannot be deleted: active
+// ones are deactivated instead so no new bookings come in, any other status is refused.
+func (vs *VenuesService) DeleteVenue(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool, accessToken string) (*VenueDeletion, error) {
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	// The booking check and the delete happen together in the database
+	now := time.Now()
+	asAdmin := actsAsAdmin(venue, userId, isAdmin)
+	if asAdmin {
+		err = vs.venuesRepo.AdminSoftDeleteVenue(ctx, venueId, now)
+	} else {
+		err = vs.venuesRepo.SoftDeleteVenue(ctx, venueId, now, accessToken)
+	}
+	if errors.Is(err, models.ErrVenueBooked) {
+		if venue.Status != models.StatusActive {
+			return nil, err
+		}
+		updated, err := vs.changeVenueStatus(ctx, venue, models.StatusInactive, bookingsDeactivationReason, userId, asAdmin, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		return &VenueDeletion{Venue: updated, Deactivated: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := vs.trashedVenue(ctx, venueId, asAdmin, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion := &VenueDeletion{Venue: updated}
+	if vs.settings.RestoreWindow > 0 {
+		restoreUntil := now.Add(vs.settings.RestoreWindow)
+		deletion.RestoreUntil = &restoreUntil
+	}
+	return deletion, nil
+}
+
+// RestoreVenue takes a venue back out of the trash with the status it had when deleted
+func (vs *VenuesService) RestoreVenue(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool, accessToken string) (*models.Venue, error) {
+	if userId == uuid.Nil || venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID or venue ID")
+	}
+
+	venue, err := vs.trashedVenue(ctx, venueId, isAdmin, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if venue.HostId != userId && !isAdmin {
+		return nil, ErrNotVenueOwner
+	}
+	if !venue.IsDeleted() {
+		return nil, ErrVenueNotDeleted
+	}
+	if vs.settings.RestoreWindow > 0 && time.Now().After(venue.DeletedAt.Add(vs.settings.RestoreWindow)) {
+		return nil, ErrRestoreWindowClosed
+	}
+
+	data := map[string]interface{}{
+		"deleted_at": nil,
+		"updated_at": time.Now(),
+	}
+	if actsAsAdmin(venue, userId, isAdmin) {
+		return vs.venuesRepo.AdminUpdateVenue(ctx, venueId, data)
+	}
+	return vs.venuesRepo.UpdateVenue(ctx, venue.HostId, venueId, data, accessToken)
+}
+
+// trashedVenue loads a venue that may be in the trash, which row level security hides from the
+// anon key: with the service role for admins and with the caller's token otherwise
+func (vs *VenuesService) trashedVenue(ctx context.Context, venueId uuid.UUID, asAdmin bool, accessToken string) (*models.Venue, error) {
+	if asAdmin {
+		return vs.venuesRepo.AdminGetVenue(ctx, venueId)
+	}
+	return vs.venuesRepo.GetVenueWithAuth(ctx, venueId, accessToken)
+}
+
+// ListDeletedVenues returns the host's venues that are in the trash
+func (vs *VenuesService) ListDeletedVenues(ctx context.Context, hostId uuid.UUID, accessToken string) ([]*models.Venue, error) {
+	if hostId == uuid.Nil {
+		return nil, fmt.Errorf("invalid host ID")
+	}
+	return vs.venuesRepo.ListDeletedVenuesByHost(ctx, hostId, accessToken)
+}
+
+// PurgeDeletedVenues permanently removes venues deleted longer ago than the restore window,
+// returning how many were removed. Venues whose row cannot be deleted are left for the next pass.
+func (vs *VenuesService) PurgeDeletedVenues(ctx context.Context) (int, error) {
+	if vs.settings.RestoreWindow <= 0 {
+		return 0, nil
+	}
+
+	expired, err := vs.venuesRepo.ListDeletedVenues(ctx, time.Now().Add(-vs.settings.RestoreWindow), venuePurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for _, venue := range expired {
+		if err := vs.purgeVenue(ctx, venue); err != nil {
+			errs = append(errs, fmt.Errorf("venue %s: %v", venue.Id, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purgeVenue deletes a venue with everything that refers to it. The row goes first, together
+// with the archiving of its bookings, so a venue that cannot be purged keeps its reviews,
+// favourites and history. The cleanup after it carries on past failures; anything it leaves
+// behind refers to a venue that no longer exists and is never shown.
+func (vs *VenuesService) purgeVenue(ctx context.Context, venue *models.Venue) error {
+	if err := vs.venuesRepo.PurgeVenue(ctx, venue.Id); err != nil {
+		return err
+	}
+
+	var errs []error
+	reviewPhotos, err := vs.reviewsRepo.DeleteVenueReviews(ctx, venue.Id)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(reviewPhotos) > 0 && vs.media != nil {
+		if err := vs.media.Delete(ctx, reviewPhotos...); err != nil {
+			fmt.Printf("Failed to delete review photos of venue %s: %v\n", venue.Id, err)
+		}
+	}
+	if err := vs.venueViewsRepo.DeleteVenueViews(ctx, venue.Id.String()); err != nil {
+		errs = append(errs, err)
+	}
+	if err := vs.favouritesRepo.RemoveItemFromAllFavourites(ctx, venue.Id.String()); err != nil {
+		errs = append(errs, err)
+	}
+	if err := vs.ratingsRepo.DeleteRatingAggregate(ctx, venue.Id); err != nil {
+		errs = append(errs, err)
+	}
+	if err := vs.slugRedirectsRepo.DeleteVenueSlugRedirects(ctx, venue.Id); err != nil {
+		errs = append(errs, err)
+	}
+	if err := vs.statusHistoryRepo.DeleteVenueStatusHistory(ctx, venue.Id); err != nil {
+		errs = append(errs, err)
+	}
+
+	vs.deleteVenueAssets(ctx, vs.venueGallery(venue))
+	return errors.Join(errs...)
+}
+
+// WatchDeletedVenues runs PurgeDeletedVenues every interval until ctx is done
+func (vs *VenuesService) WatchDeletedVenues(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := vs.PurgeDeletedVenues(ctx)
+			if err != nil {
+				fmt.Printf("[venues] failed to purge deleted venues: %v\n", err)
+			}
+			if purged > 0 {
+				fmt.Printf("[venues] purged %d deleted venues\n", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/storage"
+)
+
+var (
+	ErrInvalidUploadCount = errors.New("upload count must be at least one")
+	ErrUploadNotFound     = errors.New("upload ticket not found")
+	ErrUploadExpired      = errors.New("upload ticket has expired")
+	ErrUploadNotReceived  = errors.New("file has not been uploaded yet")
+)
+
+const (
+	// uploadConfirmGrace is how long after its ticket expires an upload may still be confirmed
+	uploadConfirmGrace = 30 * time.Minute
+	// uploadGCBatch bounds the expired uploads removed per collection pass
+	uploadGCBatch = 100
+)
+
+// IssueVenueImageUploads hands out count signed tickets for uploading gallery images straight
+// to storage. The files only join the gallery once ConfirmVenueImageUploads is called.
+func (vs *VenuesService) IssueVenueImageUploads(ctx context.Context, userId, venueId uuid.UUID, count int, isAdmin bool) ([]*storage.UploadTicket, error) {
+	if count < 1 {
+		return nil, ErrInvalidUploadCount
+	}
+	if vs.media == nil {
+		return nil, fmt.Errorf("image storage is not configured")
+	}
+
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if vs.settings.MaxImages > 0 && len(venue.Images)+count > vs.settings.MaxImages {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyVenueImages, vs.settings.MaxImages)
+	}
+
+	now := time.Now()
+	tickets := make([]*storage.UploadTicket, 0, count)
+	pending := make([]*models.PendingUpload, 0, count)
+	for i := 0; i < count; i++ {
+		publicID := path.Join(storage.UploadsFolder, strings.ReplaceAll(uuid.NewString(), "-", ""))
+		ticket, err := vs.media.UploadTicket(publicID, vs.settings.UploadTicketTTL, vs.settings.MaxImageBytes)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+		pending = append(pending, &models.PendingUpload{
+			PublicID:  ticket.PublicID,
+			OwnerID:   userId,
+			Purpose:   models.UploadPurposeVenueImage,
+			TargetID:  venueId,
+			CreatedAt: now,
+			ExpiresAt: ticket.ExpiresAt.Add(uploadConfirmGrace),
+		})
+	}
+
+	if err := vs.pendingUploadsRepo.CreatePendingUploads(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// ConfirmVenueImageUploads checks files uploaded with tickets from IssueVenueImageUploads,
+// processes them like any other gallery image and appends them to the gallery.
+// Raw uploads that fail are left for the client to retry until the garbage collector takes them.
+func (vs *VenuesService) ConfirmVenueImageUploads(ctx context.Context, userId, venueId uuid.UUID, publicIDs []string, isAdmin bool, accessToken string) (*models.Venue, error) {
+	if len(publicIDs) == 0 {
+		return nil, ErrNoVenueImages
+	}
+	if vs.media == nil {
+		return nil, fmt.Errorf("image storage is not configured")
+	}
+
+	venue, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if vs.settings.MaxImages > 0 && len(venue.Images)+len(publicIDs) > vs.settings.MaxImages {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyVenueImages, vs.settings.MaxImages)
+	}
+
+	records, err := vs.pendingUploadsRepo.GetPendingUploads(ctx, publicIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.PendingUpload, len(records))
+	for _, record := range records {
+		byID[record.PublicID] = record
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(publicIDs))
+	for _, publicID := range publicIDs {
+		record, ok := byID[publicID]
+		if !ok || seen[publicID] || record.OwnerID != userId || record.TargetID != venueId ||
+			record.Purpose != models.UploadPurposeVenueImage {
+			return nil, fmt.Errorf("%w: %s", ErrUploadNotFound, publicID)
+		}
+		if now.After(record.ExpiresAt) {
+			return nil, fmt.Errorf("%w: %s", ErrUploadExpired, publicID)
+		}
+		seen[publicID] = true
+	}
+
+	added := make([]galleryImage, 0, len(publicIDs))
+	for _, publicID := range publicIDs {
+		image, err := vs.processUpload(ctx, publicID)
+		if err != nil {
+			vs.deleteVenueAssets(ctx, added)
+			return nil, err
+		}
+		added = append(added, image)
+	}
+
+	gallery := append(vs.venueGallery(venue), added...)
+	updated, err := vs.saveNewVenueImages(ctx, venue, gallery, userId, accessToken)
+	if err != nil {
+		vs.deleteVenueAssets(ctx, added)
+		return nil, err
+	}
+
+	// The processed copies are in the gallery; the raw uploads and their tickets can go
+	if err := vs.media.Delete(ctx, publicIDs...); err != nil {
+		fmt.Printf("Failed to delete confirmed uploads: %v\n", err)
+	}
+	if err := vs.pendingUploadsRepo.DeletePendingUploads(ctx, publicIDs); err != nil {
+		fmt.Printf("Failed to delete pending upload records: %v\n", err)
+	}
+
+	return updated, nil
+}
+
+// processUpload verifies a raw direct upload and stores a processed copy of it
+func (vs *VenuesService) processUpload(ctx context.Context, publicID string) (galleryImage, error) {
+	info, err := vs.media.Stat(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAssetNotFound) {
+			return galleryImage{}, fmt.Errorf("%w: %s", ErrUploadNotReceived, publicID)
+		}
+		return galleryImage{}, err
+	}
+	if vs.settings.MaxImageBytes > 0 && info.Bytes > vs.settings.MaxImageBytes {
+		return galleryImage{}, fmt.Errorf("%w: %s", ErrVenueImageTooLarge, publicID)
+	}
+
+	rc, err := vs.media.Open(ctx, publicID)
+	if err != nil {
+		return galleryImage{}, err
+	}
+	defer rc.Close()
+
+	image, err := vs.storeVenueImage(ctx, rc)
+	if err != nil {
+		return galleryImage{}, venueImageError(err, publicID)
+	}
+	return image, nil
+}
+
+// CollectExpiredUploads deletes unconfirmed direct uploads whose tickets have expired,
+// returning how many were removed
+func (vs *VenuesService) CollectExpiredUploads(ctx context.Context) (int, error) {
+	if vs.media == nil {
+		return 0, nil
+	}
+
+	removed := 0
+	for {
+		expired, err := vs.pendingUploadsRepo.ListExpiredPendingUploads(ctx, time.Now(), uploadGCBatch)
+		if err != nil {
+			return removed, err
+		}
+		if len(expired) == 0 {
+			return removed, nil
+		}
+
+		ids := make([]string, len(expired))
+		for i, upload := range expired {
+			ids[i] = upload.PublicID
+		}
+		// Records are kept when storage fails so the next pass retries them
+		if err := vs.media.Delete(ctx, ids...); err != nil {
+			return removed, err
+		}
+		if err := vs.pendingUploadsRepo.DeletePendingUploads(ctx, ids); err != nil {
+			return removed, err
+		}
+		removed += len(ids)
+
+		if len(expired) < uploadGCBatch {
+			return removed, nil
+		}
+	}
+}
+
+// WatchExpiredUploads runs CollectExpiredUploads every interval until ctx is done
+func (vs *VenuesService) WatchExpiredUploads(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := vs.CollectExpiredUploads(ctx)
+			if err != nil {
+				fmt.Printf("[uploads] failed to collect expired uploads: %v\n", err)
+			}
+			if removed > 0 {
+				fmt.Printf("[uploads] removed %d unconfirmed uploads\n", removed)
+			}
+		}
+	}
+}
@@ -2,26 +2,156 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/joshua-takyi/ww/internal/connect"
+	"github.com/joshua-takyi/ww/internal/geo"
 	"github.com/joshua-takyi/ww/internal/helpers"
 	"github.com/joshua-takyi/ww/internal/models"
+	"github.com/joshua-takyi/ww/internal/moderation"
+	"github.com/joshua-takyi/ww/internal/search"
+	"github.com/joshua-takyi/ww/internal/storage"
 )
 
+var (
+	ErrNotVenueOwner     = errors.New("you can only modify your own venues")
+	ErrNoVenueChanges    = errors.New("no fields to update")
+	ErrInvalidVenueField = errors.New("invalid venue field")
+)
+
+// editableVenueFields are the columns a host may change through UpdateVenue.
+// Images, slug, status and ratings are managed by their own flows.
+var editableVenueFields = map[string]bool{
+	"name":                       true,
+	"vibe_headline":              true,
+	"description":                true,
+	"venue_type":                 true,
+	"tags":                       true,
+	"region":                     true,
+	"capacity":                   true,
+	"seating_capacity":           true,
+	"standing_capacity":          true,
+	"ceiling_height_feet":        true,
+	"location":                   true,
+	"coordinates":                true,
+	"accessibility":              true,
+	"load_in_access":             true,
+	"amenities":                  true,
+	"rules":                      true,
+	"alcohol_policy":             true,
+	"external_catering_allowed":  true,
+	"price_model":                true,
+	"price_per_hour":             true,
+	"min_booking_duration_hours": true,
+	"fixed_price_package_price":  true,
+	"package_duration_hours":     true,
+	"overtime_rate_per_hour":     true,
+	"cleaning_fee":               true,
+	"security_deposit":           true,
+	"setup_takedown_duration":    true,
+	"included_items":             true,
+	"cancellation_policy":        true,
+	"availability":               true,
+}
+
+// pricingVenueFields are written together because pricing normalisation may clear any of them
+var pricingVenueFields = []string{
+	"price_model",
+	"price_per_hour",
+	"min_booking_duration_hours",
+	"fixed_price_package_price",
+	"package_duration_hours",
+}
+
+// VenueSettings holds the configurable venue policies
+type VenueSettings struct {
+	// MaxImages is the maximum number of images in a venue gallery
+	MaxImages int
+	// MaxImageBytes is the maximum size of a single uploaded venue image
+	MaxImageBytes int64
+	// UploadTicketTTL is how long a direct upload ticket stays valid
+	UploadTicketTTL time.Duration
+	// RestoreWindow is how long a deleted venue can be restored before it is purged
+	RestoreWindow time.Duration
+}
+
 type VenuesService struct {
-	venuesRepo     models.VenuesRepo
-	venueViewsRepo models.VenueViewsRepo
+	venuesRepo         models.VenuesRepo
+	venueViewsRepo     models.VenueViewsRepo
+	ratingsRepo        models.RatingAggregatesRepo
+	slugRedirectsRepo  models.SlugRedirectsRepo
+	pendingUploadsRepo models.PendingUploadsRepo
+	statusHistoryRepo  models.VenueStatusHistoryRepo
+	bookingsRepo       models.BookingsRepo
+	favouritesRepo     models.FavouriteRepo
+	reviewsRepo        models.ReviewsRepo
+	locator            models.VenueLocator
+	moderator          *moderation.Moderator
+	media              storage.MediaStore
+	settings           VenueSettings
+	searchIndex        *search.Index
+	suggester          *search.Suggester
 }
 
-func NewVenuesService(venuesRepo models.VenuesRepo, venueViewsRepo models.VenueViewsRepo) *VenuesService {
+func NewVenuesService(venuesRepo models.VenuesRepo, venueViewsRepo models.VenueViewsRepo, ratingsRepo models.RatingAggregatesRepo, slugRedirectsRepo models.SlugRedirectsRepo, pendingUploadsRepo models.PendingUploadsRepo, statusHistoryRepo models.VenueStatusHistoryRepo, bookingsRepo models.BookingsRepo, favouritesRepo models.FavouriteRepo, reviewsRepo models.ReviewsRepo, locator models.VenueLocator, moderator *moderation.Moderator, media storage.MediaStore, settings VenueSettings) *VenuesService {
 	return &VenuesService{
-		venuesRepo:     venuesRepo,
-		venueViewsRepo: venueViewsRepo,
+		venuesRepo:         venuesRepo,
+		venueViewsRepo:     venueViewsRepo,
+		ratingsRepo:        ratingsRepo,
+		slugRedirectsRepo:  slugRedirectsRepo,
+		pendingUploadsRepo: pendingUploadsRepo,
+		statusHistoryRepo:  statusHistoryRepo,
+		bookingsRepo:       bookingsRepo,
+		favouritesRepo:     favouritesRepo,
+		reviewsRepo:        reviewsRepo,
+		locator:            locator,
+		moderator:          moderator,
+		media:              media,
+		settings:           settings,
+		searchIndex:        newVenueSearchIndex(),
+		suggester:          search.NewSuggester(),
+	}
+}
+
+func (vs *VenuesService) EnsureIndexes(ctx context.Context) error {
+	if err := vs.slugRedirectsRepo.EnsureSlugRedirectIndexes(ctx); err != nil {
+		return err
+	}
+	if err := vs.pendingUploadsRepo.EnsurePendingUploadIndexes(ctx); err != nil {
+		return err
 	}
+	return vs.statusHistoryRepo.EnsureVenueStatusHistoryIndexes(ctx)
+}
+
+// moderateDescription masks offending terms in a venue description. New venues already wait
+// for admin approval in pending status, which is where held descriptions are reviewed: the
+// hold is kept in the status reason so the review queue shows why the venue needs a look.
+func (vs *VenuesService) moderateDescription(ctx context.Context, venue *models.Venue) moderation.Result {
+	result := vs.moderator.Moderate(ctx, moderation.SurfaceVenueDescription, venue.Description)
+	venue.Description = result.Text
+	if result.Held() {
+		venue.StatusReason = heldDescriptionReason + ": " + strings.Join(result.Reasons, ", ")
+	} else if descriptionHeld(venue) {
+		venue.StatusReason = ""
+	}
+	return result
+}
+
+// attachRatingSummary adds the full rating breakdown to a venue for detail views
+func (vs *VenuesService) attachRatingSummary(ctx context.Context, venue *models.Venue) {
+	if venue == nil {
+		return
+	}
+	summary, err := vs.ratingsRepo.GetRatingAggregate(ctx, venue.Id)
+	if err != nil {
+		fmt.Printf("Failed to load rating summary for venue %s: %v\n", venue.Id, err)
+		return
+	}
+	venue.RatingSummary = summary
 }
 
 func ValidateAndNormalizeVenuePricing(v *models.Venue) error {
@@ -73,6 +203,12 @@ func (vs *VenuesService) CreateVenue(ctx context.Context, venue *models.Venue, h
 	if err := models.Validate.Struct(venue); err != nil {
 		return nil, fmt.Errorf("invalid venue data provided: %v", err)
 	}
+	if err := validateVenueValues(venue); err != nil {
+		return nil, err
+	}
+	if err := normalizeVenueAmenities(venue); err != nil {
+		return nil, err
+	}
 
 	if err := ValidateAndNormalizeVenuePricing(venue); err != nil {
 		return nil, err
@@ -85,83 +221,71 @@ func (vs *VenuesService) CreateVenue(ctx context.Context, venue *models.Venue, h
 		venue.Id = uuid.New()
 	}
 
-	// Upload images first if any
-	var uploadedPublicIDs []string
-	if len(venue.Images) > 0 {
-		// Upload images with timeout
-		uploadChan := make(chan struct {
-			urls      []string
-			publicIDs []string
-		}, 1)
-		errorChan := make(chan error, 1)
-
-		go func() {
-			urls, publicIDs, uploadErr := helpers.UploadImages(ctx, connect.Cld, venue.Images, helpers.VenueFolder)
-			if uploadErr != nil {
-				errorChan <- uploadErr
-				return
-			}
-			uploadChan <- struct {
-				urls      []string
-				publicIDs []string
-			}{urls, publicIDs}
-		}()
-
-		// Wait for upload with timeout
-		select {
-		case result := <-uploadChan:
-			venue.Images = result.urls
-			uploadedPublicIDs = result.publicIDs
-			fmt.Printf("Successfully uploaded %d images\n", len(result.urls))
-		case uploadErr := <-errorChan:
-			return nil, fmt.Errorf("failed to upload images: %v", uploadErr)
-		case <-time.After(30 * time.Second): // Increased timeout for multiple images
-			return nil, fmt.Errorf("image upload timeout")
-		}
+	// Images only arrive through the upload endpoints once the venue exists
+	if hasVenueImages(venue) {
+		return nil, ErrVenueImagesOnCreate
 	}
 
 	venue.HostId = hostId
 	venue.CreatedAt = now
 	venue.UpdatedAt = now
 	venue.Status = models.StatusPending
+	vs.moderateDescription(ctx, venue)
 
-	// Create the venue in the database with the uploaded image URLs
+	// Create the venue in the database
 	createdVenue, err := vs.venuesRepo.CreateVenue(ctx, venue, hostId, accessToken)
 	if err != nil {
-		// If venue creation fails, clean up uploaded images
-		if len(uploadedPublicIDs) > 0 {
-			helpers.DeleteImages(ctx, connect.Cld, helpers.VenueFolder, uploadedPublicIDs)
-		}
 		return nil, err
 	}
 
+	vs.recordStatusChange(ctx, &models.VenueStatusChange{
+		VenueID:   createdVenue.Id,
+		To:        models.StatusPending,
+		ActorID:   hostId,
+		CreatedAt: now,
+	})
 	return createdVenue, nil
 }
 
-func (vs *VenuesService) ListVenues(ctx context.Context, offset, limit int) ([]*models.Venue, int, error) {
+// ListVenues lists the public (active) venues, newest first unless another sort is given
+func (vs *VenuesService) ListVenues(ctx context.Context, sortBy models.VenueSort, page models.PageRequest) ([]*models.Venue, models.PageInfo, error) {
 
 	// Validate input parameters
-	if offset < 0 || limit <= 0 {
-		return nil, 0, fmt.Errorf("invalid offset or limit")
+	if page.Offset < 0 || page.Limit <= 0 {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid offset or limit")
 	}
 
-	return vs.venuesRepo.ListVenues(ctx, offset, limit)
+	query := models.VenueSearchQuery{Status: models.StatusActive, Sort: sortBy}
+	if err := query.Validate(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	if query.Sort == models.SortDistance {
+		return nil, models.PageInfo{}, fmt.Errorf("%w: sorting by distance needs a search point", models.ErrInvalidVenueSearch)
+	}
+	if query.Sort == models.SortRelevance {
+		return nil, models.PageInfo{}, fmt.Errorf("%w: sorting by relevance needs a text query", models.ErrInvalidVenueSearch)
+	}
+	return vs.pageVenues(ctx, query, nil, page)
 }
 
-func (vs *VenuesService) ListVenueByID(ctx context.Context, id uuid.UUID) (*models.Venue, error) {
+// ListVenueByID returns a venue if the viewer may see it; venues that are not active are
+// only visible to their host and admins
+func (vs *VenuesService) ListVenueByID(ctx context.Context, id, viewerId uuid.UUID, isAdmin bool) (*models.Venue, error) {
 	if id == uuid.Nil {
 		return nil, fmt.Errorf("invalid venue ID")
 	}
 
-	return vs.venuesRepo.ListVenueByID(ctx, id)
-}
-
-func (vs *VenuesService) DeleteVenue(ctx context.Context, host_id uuid.UUID, venue_id uuid.UUID, accessToken string) error {
-	if host_id == uuid.Nil || venue_id == uuid.Nil {
-		return fmt.Errorf("invalid host ID or venue ID")
+	venue, err := vs.venuesRepo.ListVenueByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	if venue.IsDeleted() || !canViewVenue(venue, viewerId, isAdmin) {
+		return nil, models.ErrVenueNotFound
+	}
+	vs.attachCompleteness(venue)
 
-	return vs.venuesRepo.DeleteVenue(ctx, host_id, venue_id, accessToken)
+	vs.attachRatingSummary(ctx, venue)
+	return venue, nil
 }
 
 func (vs *VenuesService) ListVenuesByHost(ctx context.Context, hostId uuid.UUID, offset, limit int, accessToken string) ([]*models.Venue, int, error) {
@@ -177,14 +301,45 @@ func (vs *VenuesService) ListVenuesByHost(ctx context.Context, hostId uuid.UUID,
 	return vs.venuesRepo.ListVenuesByHost(ctx, hostId, offset, limit, accessToken)
 }
 
-func (vs *VenuesService) QueryVenues(ctx context.Context, query map[string]interface{}, offset, limit int) ([]*models.Venue, int, error) {
-	if offset < 0 || limit <= 0 {
-		return nil, 0, fmt.Errorf("invalid offset or limit")
+// QueryVenues filters active venues. With an area, only venues inside it are returned with
+// their distance, sorted by distance from its centre unless another sort is given.
+func (vs *VenuesService) QueryVenues(ctx context.Context, query models.VenueSearchQuery, area *geo.Area, page models.PageRequest) ([]*models.Venue, models.PageInfo, error) {
+	if page.Offset < 0 || page.Limit <= 0 {
+		return nil, models.PageInfo{}, fmt.Errorf("invalid offset or limit")
+	}
+	if err := prepareVenueSearch(&query, area); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	return vs.pageVenues(ctx, query, area, page)
+}
+
+// prepareVenueSearch validates a public search and fills in its defaults
+func prepareVenueSearch(query *models.VenueSearchQuery, area *geo.Area) error {
+	if query.IsEmpty() && area == nil {
+		return fmt.Errorf("%w: at least one filter is required", models.ErrInvalidVenueSearch)
+	}
+	if err := query.Validate(); err != nil {
+		return err
 	}
-	if len(query) == 0 {
-		return nil, 0, fmt.Errorf("query parameters cannot be empty")
+
+	// Text searches rank by relevance unless asked otherwise, point searches by distance
+	hasPoint := area != nil && area.Center != nil
+	if query.Sort == "" && query.Text != "" {
+		query.Sort = models.SortRelevance
+	}
+	if query.Sort == "" && hasPoint {
+		query.Sort = models.SortDistance
+	}
+	if query.Sort == models.SortDistance && !hasPoint {
+		return fmt.Errorf("%w: sorting by distance needs lat and lng", models.ErrInvalidVenueSearch)
 	}
-	return vs.venuesRepo.QueryVenues(ctx, query, offset, limit)
+	if query.Sort == models.SortRelevance && query.Text == "" {
+		return fmt.Errorf("%w: sorting by relevance needs a text query", models.ErrInvalidVenueSearch)
+	}
+
+	// Search is public, so it only ever returns active venues
+	query.Status = models.StatusActive
+	return nil
 }
 
 func (vs *VenuesService) GetVenueBySlug(ctx context.Context, slug string) (*models.Venue, error) {
@@ -192,7 +347,250 @@ func (vs *VenuesService) GetVenueBySlug(ctx context.Context, slug string) (*mode
 		return nil, fmt.Errorf("invalid slug")
 	}
 
-	return vs.venuesRepo.GetVenueBySlug(ctx, slug)
+	venue, err := vs.venuesRepo.GetVenueBySlug(ctx, slug)
+	if errors.Is(err, models.ErrVenueNotFound) {
+		// Former slugs keep resolving after a venue is renamed
+		redirect, redirectErr := vs.slugRedirectsRepo.GetSlugRedirect(ctx, slug)
+		if redirectErr != nil {
+			return nil, redirectErr
+		}
+		if redirect != nil {
+			venue, err = vs.venuesRepo.ListVenueByID(ctx, redirect.VenueID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if venue.Status != models.StatusActive || venue.IsDeleted() {
+		return nil, models.ErrVenueNotFound
+	}
+
+	vs.attachRatingSummary(ctx, venue)
+	return venue, nil
+}
+
+// authorizeVenueEdit loads a venue and checks that userId may modify it
+func (vs *VenuesService) authorizeVenueEdit(ctx context.Context, userId, venueId uuid.UUID, isAdmin bool) (*models.Venue, error) {
+	if userId == uuid.Nil || venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID or venue ID")
+	}
+
+	venue, err := vs.venuesRepo.ListVenueByID(ctx, venueId)
+	if err != nil {
+		return nil, err
+	}
+	if venue.IsDeleted() {
+		return nil, models.ErrVenueNotFound
+	}
+	if venue.HostId != userId && !isAdmin {
+		return nil, ErrNotVenueOwner
+	}
+	return venue, nil
+}
+
+// UpdateVenue applies a partial update to a venue owned by userId (admins may edit any venue).
+// The update is merged onto the stored venue and the result is validated as a whole.
+func (vs *VenuesService) UpdateVenue(ctx context.Context, userId, venueId uuid.UUID, updates map[string]interface{}, isAdmin bool, accessToken string) (*models.Venue, error) {
+	if userId == uuid.Nil || venueId == uuid.Nil {
+		return nil, fmt.Errorf("invalid user ID or venue ID")
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoVenueChanges
+	}
+	for field := range updates {
+		if !editableVenueFields[field] {
+			return nil, fmt.Errorf("%w: %s cannot be updated", ErrInvalidVenueField, field)
+		}
+	}
+
+	existing, err := vs.authorizeVenueEdit(ctx, userId, venueId, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := mergeVenueUpdate(existing, updates)
+	if err != nil {
+		return nil, err
+	}
+	// Drafts may be incomplete; they are validated in full when submitted
+	validate := validateVenueUpdate
+	if existing.Status == models.StatusDraft {
+		validate = validateVenueDraft
+	}
+	if err := validate(merged); err != nil {
+		return nil, err
+	}
+	if _, ok := updates["amenities"]; ok {
+		if err := normalizeVenueAmenities(merged); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make([]string, 0, len(updates)+len(pricingVenueFields)+2)
+	touchesPricing := false
+	for field := range updates {
+		columns = append(columns, field)
+		for _, pricing := range pricingVenueFields {
+			if field == pricing {
+				touchesPricing = true
+			}
+		}
+	}
+	if touchesPricing {
+		columns = append(columns, pricingVenueFields...)
+	}
+
+	if _, ok := updates["description"]; ok && existing.Status != models.StatusDraft {
+		// Pending venues are reviewed before going live; live ones cannot take held text.
+		// Drafts are moderated when they are submitted.
+		if result := vs.moderateDescription(ctx, merged); result.Held() && existing.Status != models.StatusPending {
+			return nil, result.Err()
+		}
+		if existing.Status == models.StatusPending {
+			columns = append(columns, "status_reason")
+		}
+	}
+
+	_, nameChanged := updates["name"]
+	_, locationChanged := updates["location"]
+	if nameChanged || locationChanged {
+		merged.Slug = venueSlug(merged)
+		columns = append(columns, "slug")
+	}
+
+	merged.UpdatedAt = time.Now()
+	columns = append(columns, "updated_at")
+
+	data, err := models.VenueColumns(merged, columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare venue update: %v", err)
+	}
+
+	// Approved details that change have to be approved again
+	var statusChange *models.VenueStatusChange
+	if touchesReviewedFields(updates) {
+		statusChange = resubmitColumns(data, existing, userId)
+	}
+
+	var updated *models.Venue
+	if actsAsAdmin(existing, userId, isAdmin) {
+		updated, err = vs.venuesRepo.AdminUpdateVenue(ctx, venueId, data)
+	} else {
+		updated, err = vs.venuesRepo.UpdateVenue(ctx, existing.HostId, venueId, data, accessToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+	vs.recordStatusChange(ctx, statusChange)
+
+	// Drafts were never public, so their old slugs need no redirect
+	if existing.Slug != "" && updated.Slug != existing.Slug && existing.Status != models.StatusDraft {
+		if err := vs.slugRedirectsRepo.SaveSlugRedirect(ctx, existing.Slug, venueId); err != nil {
+			fmt.Printf("Failed to save slug redirect for venue %s: %v\n", venueId, err)
+		}
+		// The new slug is live now, so a redirect still claiming it is stale
+		if err := vs.slugRedirectsRepo.DeleteSlugRedirect(ctx, updated.Slug); err != nil {
+			fmt.Printf("Failed to clear slug redirect %q: %v\n", updated.Slug, err)
+		}
+	}
+
+	vs.attachCompleteness(updated)
+	return updated, nil
+}
+
+// mergeVenueUpdate applies a partial JSON update on top of an existing venue. Values are
+// decoded through the Venue JSON tags so type mistakes are reported per field.
+func mergeVenueUpdate(existing *models.Venue, updates map[string]interface{}) (*models.Venue, error) {
+	raw, err := json.Marshal(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode venue: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode venue: %v", err)
+	}
+	for field, value := range updates {
+		fields[field] = value
+	}
+
+	raw, err = json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode venue update: %v", err)
+	}
+	var merged models.Venue
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %s must be of type %s", ErrInvalidVenueField, typeErr.Field, typeErr.Type)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVenueField, err)
+	}
+
+	return &merged, nil
+}
+
+// validateVenueUpdate checks a merged venue field by field before it is written
+func validateVenueUpdate(v *models.Venue) error {
+	v.Name = helpers.StringTrim(v.Name)
+	v.Location = helpers.StringTrim(v.Location)
+	v.PriceModel = strings.ToUpper(helpers.StringTrim(v.PriceModel))
+	if v.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidVenueField)
+	}
+	if err := models.Validate.Struct(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVenueField, err)
+	}
+	if err := validateVenueValues(v); err != nil {
+		return err
+	}
+
+	if err := ValidateAndNormalizeVenuePricing(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVenueField, err)
+	}
+	return nil
+}
+
+// validateVenueValues rejects values that are wrong whether or not the listing is complete
+func validateVenueValues(v *models.Venue) error {
+	counts := map[string]int{
+		"capacity":            v.Capacity,
+		"seating_capacity":    v.SeatingCapacity,
+		"standing_capacity":   v.StandingCapacity,
+		"ceiling_height_feet": v.CeilingHeightFeet,
+	}
+	for field, n := range counts {
+		if n < 0 {
+			return fmt.Errorf("%w: %s cannot be negative", ErrInvalidVenueField, field)
+		}
+	}
+	amounts := map[string]float64{
+		"overtime_rate_per_hour":  v.OverTimeRatePerHour,
+		"cleaning_fee":            v.CleaningFee,
+		"security_deposit":        v.SecurityDeposit,
+		"setup_takedown_duration": v.SetupTakedownDuration,
+	}
+	for field, n := range amounts {
+		if n < 0 {
+			return fmt.Errorf("%w: %s cannot be negative", ErrInvalidVenueField, field)
+		}
+	}
+	if v.Coordinates.Latitude < -90 || v.Coordinates.Latitude > 90 ||
+		v.Coordinates.Longitude < -180 || v.Coordinates.Longitude > 180 {
+		return fmt.Errorf("%w: coordinates are out of range", ErrInvalidVenueField)
+	}
+	return nil
+}
+
+// normalizeVenueAmenities checks amenities against the catalogue. It only runs when a request
+// sets amenities: venues listed before the catalogue may still carry free-form ones, which
+// must not block unrelated edits.
+func normalizeVenueAmenities(v *models.Venue) error {
+	amenities, err := models.NormalizeAmenities(v.Amenities)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVenueField, err)
+	}
+	v.Amenities = amenities
+	return nil
 }
 
 func (vs *VenuesService) CreateManyVenues(ctx context.Context, venues []*models.Venue, hostId uuid.UUID, accessToken string) ([]*models.Venue, error) {
@@ -204,9 +602,18 @@ func (vs *VenuesService) CreateManyVenues(ctx context.Context, venues []*models.
 		if err := models.Validate.Struct(v); err != nil {
 			return nil, fmt.Errorf("invalid venue data provided: %v", err)
 		}
+		if err := validateVenueValues(v); err != nil {
+			return nil, err
+		}
+		if err := normalizeVenueAmenities(v); err != nil {
+			return nil, err
+		}
 		if err := ValidateAndNormalizeVenuePricing(v); err != nil {
 			return nil, err
 		}
+		if hasVenueImages(v) {
+			return nil, ErrVenueImagesOnCreate
+		}
 		s := helpers.GenerateSlug(v.Name, v.Location)
 		v.Slug = s
 		if v.Id == uuid.Nil {
@@ -216,9 +623,23 @@ func (vs *VenuesService) CreateManyVenues(ctx context.Context, venues []*models.
 		v.CreatedAt = time.Now()
 		v.UpdatedAt = time.Now()
 		v.Status = models.StatusPending
+		vs.moderateDescription(ctx, v)
 	}
 
-	return vs.venuesRepo.CreateManyVenues(ctx, venues, hostId, accessToken)
+	created, err := vs.venuesRepo.CreateManyVenues(ctx, venues, hostId, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range created {
+		vs.recordStatusChange(ctx, &models.VenueStatusChange{
+			VenueID:   v.Id,
+			To:        models.StatusPending,
+			ActorID:   hostId,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+	return created, nil
 }
 
 func (vs *VenuesService) TrackVenueView(ctx context.Context, venueId uuid.UUID, userId *uuid.UUID, sessionId, ipAddress, userAgent string) error {
@@ -251,8 +672,28 @@ func (vs *VenuesService) GetVenueViewStats(ctx context.Context, venueId uuid.UUI
 	return vs.venueViewsRepo.GetVenueViewStats(ctx, venueId.String(), days)
 }
 
-func (vs *VenuesService) GetVenueViewHistory(ctx context.Context, venueId uuid.UUID, limit int) ([]*models.VenueView, error) {
-	return vs.venueViewsRepo.GetVenueViewHistory(ctx, venueId.String(), limit)
+// GetVenueViewHistory returns a page of a venue's view records, newest first
+func (vs *VenuesService) GetVenueViewHistory(ctx context.Context, venueId uuid.UUID, page models.PageRequest) ([]*models.VenueView, models.PageInfo, error) {
+	scope := "views:venue:" + venueId.String()
+	cursor, err := models.DecodeCursor(page.Cursor, scope)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	views, err := vs.venueViewsRepo.GetVenueViewHistory(ctx, venueId.String(), cursor, page.Limit+1)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	views, info := viewHistoryPage(scope, cursor, views, page.Limit)
+
+	if page.WithTotal {
+		counts, err := vs.venueViewsRepo.CountVenueViews(ctx, []string{venueId.String()})
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		info.Total = int(counts[venueId.String()])
+	}
+	return views, info, nil
 }
 
 // GetHostViewStats returns aggregated view statistics for all venues owned by a host
@@ -261,6 +702,35 @@ func (vs *VenuesService) GetHostViewStats(ctx context.Context, hostId uuid.UUID,
 }
 
 // GetHostViewHistory returns recent view records for all venues owned by a host
-func (vs *VenuesService) GetHostViewHistory(ctx context.Context, hostId uuid.UUID, limit int) ([]*models.VenueView, error) {
-	return vs.venueViewsRepo.GetHostViewHistory(ctx, hostId.String(), limit)
+func (vs *VenuesService) GetHostViewHistory(ctx context.Context, hostId uuid.UUID, page models.PageRequest) ([]*models.VenueView, models.PageInfo, error) {
+	scope := "views:host:" + hostId.String()
+	cursor, err := models.DecodeCursor(page.Cursor, scope)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	views, err := vs.venueViewsRepo.GetHostViewHistory(ctx, hostId.String(), cursor, page.Limit+1)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	views, info := viewHistoryPage(scope, cursor, views, page.Limit)
+
+	if page.WithTotal {
+		total, err := vs.venueViewsRepo.CountHostViews(ctx, hostId.String())
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		info.Total = int(total)
+	}
+	return views, info, nil
+}
+
+// viewHistoryPage trims the extra view fetched past limit and locates the page
+func viewHistoryPage(scope string, cursor *models.Cursor, views []*models.VenueView, limit int) ([]*models.VenueView, models.PageInfo) {
+	views, hasMore := models.TrimPage(views, limit, cursor != nil && cursor.Backward)
+	if len(views) == 0 {
+		return views, models.PageInfo{}
+	}
+	first, last := models.ViewCursorKey(views[0]), models.ViewCursorKey(views[len(views)-1])
+	return views, models.KeysetPage(scope, cursor, first, last, hasMore)
 }
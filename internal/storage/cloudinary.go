@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/cloudinary/cloudinary-go/v2/transformation"
+)
+
+// A Cloudinary transformation URL segment such as "w_400,h_300,c_fill"
+var reTransformation = regexp.MustCompile(`^[a-z]{1,3}_[^,/]+(,[a-z]{1,3}_[^,/]+)*$`)
+
+// CloudinaryStore keeps media on Cloudinary
+type CloudinaryStore struct {
+	cld *cloudinary.Cloudinary
+}
+
+func NewCloudinaryStore(cld *cloudinary.Cloudinary) *CloudinaryStore {
+	return &CloudinaryStore{cld: cld}
+}
+
+func (s *CloudinaryStore) Upload(ctx context.Context, r io.Reader, folder string) (*Asset, error) {
+	return s.upload(ctx, r, folder)
+}
+
+func (s *CloudinaryStore) UploadSource(ctx context.Context, source, folder string) (*Asset, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, ErrInvalidSource
+	}
+	// Cloudinary fetches remote URLs and reads local paths itself
+	return s.upload(ctx, source, folder)
+}
+
+func (s *CloudinaryStore) Put(ctx context.Context, r io.Reader, publicID string) (*Asset, error) {
+	if strings.TrimSpace(publicID) == "" {
+		return nil, ErrInvalidPublicID
+	}
+	return s.send(ctx, r, uploader.UploadParams{
+		PublicID:   publicID,
+		Overwrite:  api.Bool(true),
+		Invalidate: api.Bool(true),
+		Tags:       []string{uploadTag},
+	})
+}
+
+func (s *CloudinaryStore) upload(ctx context.Context, file interface{}, folder string) (*Asset, error) {
+	return s.send(ctx, file, uploader.UploadParams{
+		Folder: folder,
+		Tags:   []string{uploadTag},
+	})
+}
+
+func (s *CloudinaryStore) send(ctx context.Context, file interface{}, params uploader.UploadParams) (*Asset, error) {
+	result, err := s.cld.Upload.Upload(ctx, file, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %v", err)
+	}
+	if result.Error.Message != "" {
+		return nil, fmt.Errorf("failed to upload image: %s", result.Error.Message)
+	}
+	return &Asset{URL: result.SecureURL, PublicID: result.PublicID}, nil
+}
+
+// cloudinarySignatureTTL is how long Cloudinary accepts a signed upload after its timestamp
+const cloudinarySignatureTTL = time.Hour
+
+// UploadTicket signs an upload to the Cloudinary upload API. Cloudinary accepts the signature
+// for an hour whatever ttl is and cannot cap the size, so both are checked on confirmation.
+func (s *CloudinaryStore) UploadTicket(publicID string, _ time.Duration, _ int64) (*UploadTicket, error) {
+	if strings.TrimSpace(publicID) == "" {
+		return nil, ErrInvalidPublicID
+	}
+
+	now := time.Now()
+	params := url.Values{}
+	params.Set("public_id", publicID)
+	params.Set("timestamp", strconv.FormatInt(now.Unix(), 10))
+	params.Set("tags", uploadTag)
+	signature, err := api.SignParameters(params, s.cld.Config.Cloud.APISecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign upload: %v", err)
+	}
+
+	fields := map[string]string{
+		"api_key":   s.cld.Config.Cloud.APIKey,
+		"signature": signature,
+	}
+	for key := range params {
+		fields[key] = params.Get(key)
+	}
+
+	return &UploadTicket{
+		PublicID:  publicID,
+		URL:       fmt.Sprintf("%s/v1_1/%s/image/upload", s.cld.Config.API.UploadPrefix, s.cld.Config.Cloud.CloudName),
+		Method:    http.MethodPost,
+		Fields:    fields,
+		FileField: "file",
+		ExpiresAt: now.Add(cloudinarySignatureTTL),
+	}, nil
+}
+
+func (s *CloudinaryStore) Stat(ctx context.Context, publicID string) (*AssetInfo, error) {
+	result, err := s.cld.Admin.Asset(ctx, admin.AssetParams{PublicID: publicID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up '%s': %v", publicID, err)
+	}
+	if result.Error.Message != "" {
+		if strings.Contains(strings.ToLower(result.Error.Message), "not found") {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("failed to look up '%s': %s", publicID, result.Error.Message)
+	}
+	return &AssetInfo{
+		PublicID: result.PublicID,
+		URL:      result.SecureURL,
+		Bytes:    int64(result.Bytes),
+		Format:   result.Format,
+	}, nil
+}
+
+func (s *CloudinaryStore) Open(ctx context.Context, publicID string) (io.ReadCloser, error) {
+	info, err := s.Stat(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	return OpenSource(ctx, info.URL)
+}
+
+func (s *CloudinaryStore) Delete(ctx context.Context, publicIDs ...string) error {
+	var errs []error
+	for _, rawID := range publicIDs {
+		publicID := strings.TrimSpace(rawID)
+		if publicID == "" {
+			continue
+		}
+
+		resp, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+			PublicID: publicID,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete '%s': %v", publicID, err))
+			continue
+		}
+
+		switch resp.Result {
+		case "ok":
+			fmt.Printf("[Cloudinary] Deleted: %s\n", publicID)
+		case "not found":
+			fmt.Printf("[Cloudinary] Not found: %s\n", publicID)
+		default:
+			fmt.Printf("[Cloudinary] Unexpected result for '%s': %s\n", publicID, resp.Result)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SignedURL signs the delivery URL. The ttl is only enforced when token based
+// authentication is configured on the account (CLOUDINARY_URL auth_token settings).
+func (s *CloudinaryStore) SignedURL(publicID string, ttl time.Duration) (string, error) {
+	img, err := s.cld.Image(publicID)
+	if err != nil {
+		return "", fmt.Errorf("failed to build image URL: %v", err)
+	}
+	img.Config.URL.SignURL = true
+	if token := img.Config.AuthToken; token.Key != "" && ttl > 0 {
+		token.Duration = int64(ttl / time.Second)
+		img.AuthToken.Config = &token
+	}
+	return img.String()
+}
+
+func (s *CloudinaryStore) VariantURL(publicID string, t Transform) (string, error) {
+	img, err := s.cld.Image(publicID)
+	if err != nil {
+		return "", fmt.Errorf("failed to build image URL: %v", err)
+	}
+	img.Transformation = transformation.RawTransformation(cloudinaryTransformation(t))
+	return img.String()
+}
+
+func cloudinaryTransformation(t Transform) string {
+	var parts []string
+	if t.Crop != "" {
+		parts = append(parts, "c_"+t.Crop)
+	}
+	if t.Width > 0 {
+		parts = append(parts, "w_"+strconv.Itoa(t.Width))
+	}
+	if t.Height > 0 {
+		parts = append(parts, "h_"+strconv.Itoa(t.Height))
+	}
+	if t.Format != "" {
+		parts = append(parts, "f_"+t.Format)
+	}
+	if t.Quality > 0 {
+		parts = append(parts, "q_"+strconv.Itoa(t.Quality))
+	} else {
+		parts = append(parts, "q_auto")
+	}
+	return strings.Join(parts, ",")
+}
+
+// PublicIDFromURL derives the public ID from a delivery URL, e.g.
+// ".../image/upload/v1712/venues/abc.jpg" gives "venues/abc"
+func (s *CloudinaryStore) PublicIDFromURL(rawURL string) string {
+	_, path, found := strings.Cut(rawURL, "/upload/")
+	if !found {
+		return ""
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	// Skip transformation segments ("w_400,c_fill") and the version ("v1712")
+	for len(segments) > 1 {
+		if reTransformation.MatchString(segments[0]) || isVersionSegment(segments[0]) {
+			segments = segments[1:]
+			continue
+		}
+		break
+	}
+	return trimExtension(strings.Join(segments, "/"))
+}
+
+func isVersionSegment(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func trimExtension(publicID string) string {
+	if ext := strings.LastIndex(publicID, "."); ext > strings.LastIndex(publicID, "/") {
+		return publicID[:ext]
+	}
+	return publicID
+}
@@ -0,0 +1,423 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// extensions maps sniffed content types to the extension files are saved with
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// LocalStore keeps media on the local filesystem and serves it from the API itself.
+// It needs no credentials, which makes it the backend for development and tests.
+// Files are served as stored; variants must be uploaded rather than transformed on delivery.
+type LocalStore struct {
+	root    string
+	baseURL string
+	prefix  string
+	secret  []byte
+}
+
+// NewLocalStore stores files below root and builds URLs from baseURL, e.g.
+// "http://localhost:8080/media". Without a secret a random one is generated, so signed
+// URLs stop validating when the process restarts.
+func NewLocalStore(root, baseURL, secret string) (*LocalStore, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, fmt.Errorf("local media directory is required")
+	}
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid media base URL %q", baseURL)
+	}
+	// Files are served below the URL's path, so it must not claim the whole API
+	if parsed.Path == "" {
+		return nil, fmt.Errorf("media base URL %q needs a path such as /media", baseURL)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate media signing key: %v", err)
+		}
+	}
+
+	return &LocalStore{
+		root:    root,
+		baseURL: parsed.String(),
+		prefix:  parsed.Path,
+		secret:  key,
+	}, nil
+}
+
+// Prefix is the URL path the store's files are served under, e.g. "/media"
+func (s *LocalStore) Prefix() string {
+	return s.prefix
+}
+
+func (s *LocalStore) Upload(ctx context.Context, r io.Reader, folder string) (*Asset, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate media ID: %v", err)
+	}
+	return s.write(r, path.Join(folder, hex.EncodeToString(id)))
+}
+
+func (s *LocalStore) Put(ctx context.Context, r io.Reader, publicID string) (*Asset, error) {
+	if _, err := s.filePath(publicID); err != nil {
+		return nil, err
+	}
+	// A replacement may have a different extension, so drop the old file first
+	if err := s.Delete(ctx, publicID); err != nil {
+		return nil, err
+	}
+	return s.write(r, strings.Trim(path.Clean("/"+publicID), "/"))
+}
+
+// write saves r as publicID plus the extension of its sniffed content type
+func (s *LocalStore) write(r io.Reader, publicID string) (*Asset, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read image: %v", err)
+	}
+	head = head[:n]
+	ext := extensions[http.DetectContentType(head)]
+
+	target, err := s.filePath(publicID + ext)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial image
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, io.MultiReader(bytes.NewReader(head), r)); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to upload image: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to upload image: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, fmt.Errorf("failed to upload image: %v", err)
+	}
+
+	return &Asset{URL: s.baseURL + "/" + publicID + ext, PublicID: publicID}, nil
+}
+
+func (s *LocalStore) UploadSource(ctx context.Context, source, folder string) (*Asset, error) {
+	rc, err := OpenSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return s.Upload(ctx, rc, folder)
+}
+
+// UploadTicket authorises a multipart POST to the file's own URL. The signature covers the
+// public ID, size limit and expiry, which travel in the query string.
+func (s *LocalStore) UploadTicket(publicID string, ttl time.Duration, maxBytes int64) (*UploadTicket, error) {
+	if _, err := s.filePath(publicID); err != nil {
+		return nil, err
+	}
+	publicID = strings.Trim(path.Clean("/"+publicID), "/")
+
+	expiresAt := time.Now().Add(ttl)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	limit := strconv.FormatInt(maxBytes, 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("max_bytes", limit)
+	query.Set("sig", s.sign(uploadSigningPath(publicID, limit), expires))
+
+	return &UploadTicket{
+		PublicID:  publicID,
+		URL:       s.baseURL + "/" + publicID + "?" + query.Encode(),
+		Method:    http.MethodPost,
+		Fields:    map[string]string{},
+		FileField: "file",
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *LocalStore) Stat(ctx context.Context, publicID string) (*AssetInfo, error) {
+	target, err := s.find(publicID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat '%s': %v", publicID, err)
+	}
+	ext := filepath.Ext(target)
+	return &AssetInfo{
+		PublicID: publicID,
+		URL:      s.baseURL + "/" + publicID + ext,
+		Bytes:    info.Size(),
+		Format:   strings.TrimPrefix(ext, "."),
+	}, nil
+}
+
+func (s *LocalStore) Open(ctx context.Context, publicID string) (io.ReadCloser, error) {
+	target, err := s.find(publicID)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+// find returns the file stored for publicID, whatever extension it was saved with
+func (s *LocalStore) find(publicID string) (string, error) {
+	base, err := s.filePath(publicID)
+	if err != nil {
+		return "", err
+	}
+	matches, _ := filepath.Glob(globEscape(base) + ".*")
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		matches = append(matches, base)
+	}
+	for _, match := range matches {
+		if !strings.HasPrefix(filepath.Base(match), ".") {
+			return match, nil
+		}
+	}
+	return "", ErrAssetNotFound
+}
+
+func (s *LocalStore) Delete(ctx context.Context, publicIDs ...string) error {
+	var errs []error
+	for _, rawID := range publicIDs {
+		publicID := strings.TrimSpace(rawID)
+		if publicID == "" {
+			continue
+		}
+		base, err := s.filePath(publicID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// The public ID has no extension; match whatever the file was saved with
+		matches, _ := filepath.Glob(globEscape(base) + ".*")
+		if info, err := os.Stat(base); err == nil && !info.IsDir() {
+			matches = append(matches, base)
+		}
+		for _, match := range matches {
+			if err := os.Remove(match); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("failed to delete '%s': %v", publicID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *LocalStore) SignedURL(publicID string, ttl time.Duration) (string, error) {
+	rawURL, err := s.assetURL(publicID)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		return rawURL, nil
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	filePath := strings.TrimPrefix(rawURL, s.baseURL)
+	return rawURL + "?expires=" + expires + "&sig=" + s.sign(filePath, expires), nil
+}
+
+// VariantURL only supports the empty transform; the local store serves files as stored
+func (s *LocalStore) VariantURL(publicID string, t Transform) (string, error) {
+	if t != (Transform{}) {
+		return "", ErrTransformUnsupported
+	}
+	return s.assetURL(publicID)
+}
+
+func (s *LocalStore) PublicIDFromURL(rawURL string) string {
+	rest, found := strings.CutPrefix(rawURL, s.baseURL+"/")
+	if !found {
+		return ""
+	}
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	return trimExtension(rest)
+}
+
+// ServeHTTP serves stored files below Prefix and accepts uploads made with an UploadTicket.
+// Signed URLs are checked for tampering and expiry; files outside the public folders are
+// only served with a valid signature.
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filePath := strings.TrimPrefix(r.URL.Path, s.prefix)
+	if r.Method == http.MethodPost {
+		s.receiveUpload(w, r, filePath)
+		return
+	}
+
+	target, err := s.filePath(filePath)
+	if err != nil || strings.HasPrefix(path.Base(filePath), ".") {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	signed := query.Has("sig") || query.Has("expires")
+	if !signed && !isPublicPath(filePath) {
+		http.Error(w, "signature required", http.StatusForbidden)
+		return
+	}
+	if signed {
+		sig := query.Get("sig")
+		expires := query.Get("expires")
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || !hmac.Equal([]byte(sig), []byte(s.sign(filePath, expires))) {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+		if time.Now().Unix() > unix {
+			http.Error(w, "link expired", http.StatusForbidden)
+			return
+		}
+	}
+
+	info, err := os.Stat(target)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	if signed {
+		w.Header().Set("Cache-Control", "private, no-store")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	}
+	http.ServeFile(w, r, target)
+}
+
+// isPublicPath reports whether a served file lies in one of the public folders
+func isPublicPath(filePath string) bool {
+	folder, _, _ := strings.Cut(strings.Trim(path.Clean("/"+filePath), "/"), "/")
+	for _, public := range publicFolders {
+		if folder == public {
+			return true
+		}
+	}
+	return false
+}
+
+// receiveUpload stores a file posted with an UploadTicket. Tickets are single use: an
+// upload is refused once a file exists under the public ID.
+func (s *LocalStore) receiveUpload(w http.ResponseWriter, r *http.Request, filePath string) {
+	publicID := strings.Trim(path.Clean("/"+filePath), "/")
+	query := r.URL.Query()
+	expires, limit := query.Get("expires"), query.Get("max_bytes")
+	unix, expErr := strconv.ParseInt(expires, 10, 64)
+	maxBytes, limitErr := strconv.ParseInt(limit, 10, 64)
+	expected := s.sign(uploadSigningPath(publicID, limit), expires)
+	if publicID == "" || expErr != nil || limitErr != nil || !hmac.Equal([]byte(query.Get("sig")), []byte(expected)) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > unix {
+		http.Error(w, "upload ticket expired", http.StatusForbidden)
+		return
+	}
+	if _, err := s.find(publicID); err == nil {
+		http.Error(w, "upload ticket already used", http.StatusConflict)
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	if maxBytes > 0 && header.Size > maxBytes {
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	asset, err := s.write(file, publicID)
+	if err != nil {
+		http.Error(w, "failed to store file", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"public_id":  asset.PublicID,
+		"secure_url": asset.URL,
+		"bytes":      header.Size,
+	})
+}
+
+// uploadSigningPath keeps upload signatures from being usable as download signatures
+func uploadSigningPath(publicID, maxBytes string) string {
+	return "upload:" + publicID + "|" + maxBytes
+}
+
+// assetURL finds the stored file for publicID and returns the URL it is served from
+func (s *LocalStore) assetURL(publicID string) (string, error) {
+	base, err := s.filePath(publicID)
+	if err != nil {
+		return "", err
+	}
+	matches, _ := filepath.Glob(globEscape(base) + ".*")
+	if len(matches) == 0 {
+		return s.baseURL + "/" + publicID, nil
+	}
+	return s.baseURL + "/" + publicID + filepath.Ext(matches[0]), nil
+}
+
+// filePath maps a public ID or URL path to a file below root, rejecting anything that escapes it
+func (s *LocalStore) filePath(publicID string) (string, error) {
+	cleaned := path.Clean("/" + strings.TrimSpace(publicID))
+	if cleaned == "/" {
+		return "", ErrInvalidPublicID
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *LocalStore) sign(filePath, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path.Clean("/" + filePath)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func globEscape(s string) string {
+	replacer := strings.NewReplacer("*", `\*`, "?", `\?`, "[", `\[`, `\`, `\\`)
+	return replacer.Replace(s)
+}
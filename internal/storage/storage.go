@@ -0,0 +1,151 @@
+// Package storage keeps uploaded media (avatars, venue galleries, review photos) behind a
+// single interface so the API can run against Cloudinary in production and against the
+// local disk in development and tests.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxSourceBytes bounds how much is read when fetching a remote source
+const maxSourceBytes = 50 << 20
+
+var sourceClient = &http.Client{Timeout: 30 * time.Second}
+
+// Folders group assets by what they belong to
+const (
+	AvatarFolder = "avatars"
+	VenueFolder  = "venues"
+	EventsFolder = "events"
+	ReviewFolder = "reviews"
+	// UploadsFolder holds direct uploads until they are confirmed
+	UploadsFolder = "uploads"
+)
+
+// publicFolders hold processed images that anyone may read. Everything else, such as raw
+// uploads that still carry their EXIF data, is only served through a signed URL.
+var publicFolders = []string{AvatarFolder, VenueFolder, EventsFolder, ReviewFolder}
+
+// Store backends selectable through MEDIA_STORE
+const (
+	BackendCloudinary = "cloudinary"
+	BackendLocal      = "local"
+)
+
+// uploadTag marks every asset uploaded by the API
+const uploadTag = "ww-app"
+
+var (
+	ErrInvalidPublicID = errors.New("invalid media public ID")
+	ErrInvalidSource   = errors.New("invalid media source")
+	// ErrTransformUnsupported is returned by stores that cannot render variants on delivery
+	ErrTransformUnsupported = errors.New("media store cannot transform images")
+	ErrAssetNotFound        = errors.New("media asset not found")
+)
+
+// Asset is a stored file: the URL it is served from and the ID used to manage it
+type Asset struct {
+	URL      string `json:"url"`
+	PublicID string `json:"public_id"`
+}
+
+// Transform describes a resized or re-encoded rendition of an image.
+// Zero values leave the corresponding property unchanged.
+type Transform struct {
+	Width  int
+	Height int
+	// Crop is how the image fits the box: "fill", "fit" or "limit"
+	Crop string
+	// Format is the output encoding, e.g. "webp" or "jpg"
+	Format  string
+	Quality int
+}
+
+// AssetInfo describes an asset already in storage
+type AssetInfo struct {
+	PublicID string
+	URL      string
+	Bytes    int64
+	// Format is the file format reported by the store, e.g. "jpg"
+	Format string
+}
+
+// UploadTicket authorises one direct upload from a client. The client sends a multipart
+// POST to URL with every entry of Fields plus the file in FileField.
+type UploadTicket struct {
+	PublicID  string            `json:"public_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	FileField string            `json:"file_field"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// MediaStore uploads, serves and deletes media files
+type MediaStore interface {
+	// Upload stores the content of r under folder
+	Upload(ctx context.Context, r io.Reader, folder string) (*Asset, error)
+	// UploadSource stores a local file path or remote http(s) URL under folder. It reads
+	// whatever it is pointed at, so it is only for trusted sources, never client input.
+	UploadSource(ctx context.Context, source, folder string) (*Asset, error)
+	// Put stores the content of r under an exact public ID, replacing any asset already there
+	Put(ctx context.Context, r io.Reader, publicID string) (*Asset, error)
+	// UploadTicket lets a client upload one file of at most maxBytes straight to storage
+	// under publicID. Stores that cannot enforce maxBytes leave it to the confirming side.
+	UploadTicket(publicID string, ttl time.Duration, maxBytes int64) (*UploadTicket, error)
+	// Stat describes a stored asset, or returns ErrAssetNotFound
+	Stat(ctx context.Context, publicID string) (*AssetInfo, error)
+	// Open reads a stored asset
+	Open(ctx context.Context, publicID string) (io.ReadCloser, error)
+	// Delete removes assets by public ID; missing assets are not an error
+	Delete(ctx context.Context, publicIDs ...string) error
+	// SignedURL returns a URL for the asset that stops working after ttl
+	SignedURL(publicID string, ttl time.Duration) (string, error)
+	// VariantURL returns the URL of a rendition of the asset transformed on delivery, or
+	// ErrTransformUnsupported
+	VariantURL(publicID string, t Transform) (string, error)
+	// PublicIDFromURL recovers the public ID from a URL served by this store, or "" if the
+	// URL does not belong to it
+	PublicIDFromURL(rawURL string) string
+}
+
+// OpenSource opens a local file path or fetches a remote http(s) URL for reading. Only
+// trusted sources may be passed: it makes no attempt to keep requests off internal hosts.
+func OpenSource(ctx context.Context, source string) (io.ReadCloser, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, ErrInvalidSource
+	}
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+		}
+		resp, err := sourceClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch image %s: %v", source, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch image %s: status %d", source, resp.StatusCode)
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(resp.Body, maxSourceBytes), resp.Body}, nil
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image %s: %v", source, err)
+	}
+	return f, nil
+}
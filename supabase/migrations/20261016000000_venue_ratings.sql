@@ -0,0 +1,11 @@
+-- Denormalised rating columns kept in step with the review aggregates (SupabaseRepo.UpdateVenueRating).
+-- Existing venues start at zero; run cmd/rebuild-ratings once to fill them from stored reviews.
+
+alter table public.venues
+    add column if not exists rating_average float8  not null default 0,
+    add column if not exists rating_count   integer not null default 0;
+
+-- Matches the key order of sort=rating: average, then count, newest and id as tie-breaks
+create index if not exists venues_active_rating_idx
+    on public.venues (rating_average desc, rating_count desc, created_at desc, id)
+    where status = 'active';
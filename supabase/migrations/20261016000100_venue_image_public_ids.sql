@@ -0,0 +1,5 @@
+-- Storage public IDs of the gallery images, index-aligned with venues.images, so removed or
+-- replaced images can be deleted from the media store (VenuesService.RemoveVenueImage).
+
+alter table public.venues
+    add column if not exists image_public_ids text[] default '{}';
@@ -0,0 +1,9 @@
+-- Resized renditions generated on upload (internal/imaging). Each column holds a JSON object
+-- keyed by size name, e.g. {"thumb": {"url": ..., "webp_url": ..., "width": 200, "height": 200}};
+-- venues hold one such object per gallery image, index-aligned with venues.images.
+
+alter table public.venues
+    add column if not exists image_variants jsonb;
+
+alter table public.profiles
+    add column if not exists avatar_variants jsonb;
@@ -0,0 +1,6 @@
+-- Lifecycle details of the current venue status (VenuesService.changeVenueStatus). The full
+-- history of changes is kept in MongoDB; these columns hold the latest one for listings.
+
+alter table public.venues
+    add column if not exists status_reason     text,
+    add column if not exists status_changed_at timestamptz;
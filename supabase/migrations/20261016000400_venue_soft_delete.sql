@@ -0,0 +1,135 @@
+-- Soft delete for venues (VenuesService.DeleteVenue, RestoreVenue and PurgeDeletedVenues).
+-- A venue sits in its host's trash while deleted_at is set and is purged for good once the
+-- restore window has passed.
+
+alter table public.venues
+    add column if not exists deleted_at timestamptz;
+
+-- The purge job reads the trash oldest first
+create index if not exists venues_deleted_at_idx
+    on public.venues (deleted_at)
+    where deleted_at is not null;
+
+-- Moves a venue to the trash unless it has pending or confirmed bookings ending after
+-- deleted_on. The check and the update run under a lock on the venue row, which
+-- bookings_require_live_venue also takes, so no booking can be made or confirmed in between.
+-- Returns no row when the venue does not exist, is already deleted or is not the caller's;
+-- otherwise whether it was deleted and how many bookings kept it from being deleted.
+create or replace function public.soft_delete_venue(target_venue uuid, deleted_on timestamptz default now())
+returns table (deleted boolean, upcoming_bookings int)
+language plpgsql
+security definer
+set search_path = public
+as $$
+declare
+    venue_host uuid;
+    upcoming   int;
+begin
+    select v.host_id into venue_host
+    from public.venues v
+    where v.id = target_venue and v.deleted_at is null
+    for update;
+    if not found or (coalesce(auth.role(), '') <> 'service_role' and venue_host is distinct from auth.uid()) then
+        return;
+    end if;
+
+    select count(*) into upcoming
+    from public.bookings b
+    where b.venues_id = target_venue
+      and b.status in ('pending', 'confirmed')
+      and b.end_time > deleted_on;
+    if upcoming > 0 then
+        return query select false, upcoming;
+        return;
+    end if;
+
+    update public.venues v
+    set deleted_at = deleted_on, updated_at = deleted_on
+    where v.id = target_venue;
+    return query select true, 0;
+end;
+$$;
+
+revoke execute on function public.soft_delete_venue(uuid, timestamptz) from public, anon;
+grant execute on function public.soft_delete_venue(uuid, timestamptz) to authenticated, service_role;
+
+-- Bookings can only be made or confirmed while their venue is out of the trash
+create or replace function public.bookings_require_live_venue()
+returns trigger
+language plpgsql
+security definer
+set search_path = public
+as $$
+begin
+    if new.status in ('pending', 'confirmed') then
+        perform 1
+        from public.venues v
+        where v.id = new.venues_id and v.deleted_at is null
+        for share;
+        if not found then
+            raise exception 'venue % is deleted', new.venues_id using errcode = 'P0001';
+        end if;
+    end if;
+    return new;
+end;
+$$;
+
+drop trigger if exists bookings_require_live_venue on public.bookings;
+create trigger bookings_require_live_venue
+    before insert or update of status on public.bookings
+    for each row execute function public.bookings_require_live_venue();
+
+-- Bookings of purged venues are kept here, without the foreign key to venues. New booking
+-- columns have to be added to this table as well.
+create table if not exists public.archived_bookings (
+    like public.bookings including defaults,
+    archived_at timestamptz not null default now()
+);
+alter table public.archived_bookings enable row level security;
+
+-- Deletes a trashed venue for good, archiving its bookings first. Only the service role may
+-- call it, and it refuses venues that still have upcoming bookings. Returns false when the
+-- venue is gone or not in the trash.
+create or replace function public.purge_venue(target_venue uuid)
+returns boolean
+language plpgsql
+security definer
+set search_path = public
+as $$
+begin
+    if coalesce(auth.role(), '') <> 'service_role' then
+        raise exception 'purge_venue requires the service role' using errcode = '42501';
+    end if;
+
+    perform 1
+    from public.venues v
+    where v.id = target_venue and v.deleted_at is not null
+    for update;
+    if not found then
+        return false;
+    end if;
+
+    if exists (
+        select 1 from public.bookings b
+        where b.venues_id = target_venue
+          and b.status in ('pending', 'confirmed')
+          and b.end_time > now()
+    ) then
+        raise exception 'venue % has upcoming bookings', target_venue using errcode = 'P0001';
+    end if;
+
+    with moved as (
+        delete from public.bookings b
+        where b.venues_id = target_venue
+        returning b.*
+    )
+    insert into public.archived_bookings
+    select moved.*, now() from moved;
+
+    delete from public.venues v where v.id = target_venue;
+    return true;
+end;
+$$;
+
+revoke execute on function public.purge_venue(uuid) from public, anon, authenticated;
+grant execute on function public.purge_venue(uuid) to service_role;
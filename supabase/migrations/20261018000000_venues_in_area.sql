@@ -0,0 +1,54 @@
+-- Geographic venue search used by GEO_SEARCH_BACKEND=postgis (SupabaseRepo.LocateVenues).
+-- venues.coordinates is a geography(Point,4326); venues without a position are stored at 0,0.
+
+create extension if not exists postgis;
+
+create index if not exists venues_active_coordinates_idx
+    on public.venues using gist (coordinates)
+    where status = 'active' and deleted_at is null;
+
+-- Returns the active venues within radius_m of the centre and inside the bounding box,
+-- nearest first when a centre is given. Arguments that do not apply are null. A box whose
+-- min_lng is greater than its max_lng crosses the antimeridian. max_results and skip_results
+-- page through the result; a null max_results returns every venue.
+create or replace function public.venues_in_area(
+    center_lat   float8 default null,
+    center_lng   float8 default null,
+    radius_m     float8 default null,
+    min_lat      float8 default null,
+    min_lng      float8 default null,
+    max_lat      float8 default null,
+    max_lng      float8 default null,
+    max_results  int    default null,
+    skip_results int    default 0
+)
+returns table (id uuid, distance_m float8)
+language sql
+stable
+as $$
+    with origin as (
+        select case
+            when center_lat is not null and center_lng is not null
+            then st_setsrid(st_makepoint(center_lng, center_lat), 4326)::geography
+        end as point
+    )
+    select v.id,
+           case when origin.point is not null then st_distance(v.coordinates, origin.point) end as distance_m
+    from public.venues v, origin
+    where v.status = 'active'
+      and v.deleted_at is null
+      and v.coordinates is not null
+      and not (st_x(v.coordinates::geometry) = 0 and st_y(v.coordinates::geometry) = 0)
+      and (radius_m is null or st_dwithin(v.coordinates, origin.point, radius_m))
+      and (min_lat is null or st_y(v.coordinates::geometry) between min_lat and max_lat)
+      and (min_lng is null or case
+            when min_lng <= max_lng then st_x(v.coordinates::geometry) between min_lng and max_lng
+            else st_x(v.coordinates::geometry) >= min_lng or st_x(v.coordinates::geometry) <= max_lng
+          end)
+    order by distance_m nulls last, v.id
+    limit max_results
+    offset coalesce(skip_results, 0);
+$$;
+
+grant execute on function public.venues_in_area(float8, float8, float8, float8, float8, float8, float8, int, int)
+    to anon, authenticated, service_role;